@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kieranajp/pairings/internal/domain/recipe"
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+	"github.com/kieranajp/pairings/internal/infrastructure/fetcher"
+	"github.com/urfave/cli/v2"
+)
+
+// CacheClearCommand implements the Command interface for purging the cache
+type CacheClearCommand struct {
+	store *cache.Store
+}
+
+// NewCacheClearCommand creates a new cache clear command
+func NewCacheClearCommand() *CacheClearCommand {
+	return &CacheClearCommand{}
+}
+
+func (c *CacheClearCommand) WithStore(store *cache.Store) *CacheClearCommand {
+	c.store = store
+	return c
+}
+
+// Name returns the name of the command
+func (c *CacheClearCommand) Name() string {
+	return "clear"
+}
+
+// Usage returns the usage description of the command
+func (c *CacheClearCommand) Usage() string {
+	return "Remove cached recipe pages and extracted recipes"
+}
+
+// Flags returns the command's flags
+func (c *CacheClearCommand) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "pages",
+			Usage: "Only remove cached recipe pages",
+		},
+		&cli.BoolFlag{
+			Name:  "recipes",
+			Usage: "Only remove cached extracted recipes",
+		},
+	}
+}
+
+// Action returns a function that will be executed when the command is run
+func (c *CacheClearCommand) Action(ctx *cli.Context) error {
+	var namespaces []string
+	if ctx.Bool("pages") {
+		namespaces = append(namespaces, fetcher.PagesNamespace)
+	}
+	if ctx.Bool("recipes") {
+		namespaces = append(namespaces, recipe.RecipesNamespace)
+	}
+
+	if err := c.store.Clear(namespaces...); err != nil {
+		return err
+	}
+
+	fmt.Printf("Cleared cache at %s\n", c.store.Dir())
+	return nil
+}
@@ -1,6 +1,10 @@
 package cmd
 
-import "github.com/urfave/cli/v2"
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
 
 // Command defines the interface that all commands must implement
 type Command interface {
@@ -16,3 +20,9 @@ type Command interface {
 	// Action executes the command with the given context
 	Action(*cli.Context) error
 }
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
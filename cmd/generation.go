@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
+	"github.com/urfave/cli/v2"
+)
+
+// GenerationFlags returns the flags that tune how the model generates its
+// responses. They are accepted globally, where they apply to every request,
+// and by the commands that ask for pairings, where they override the global
+// values for that command's request.
+func GenerationFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "system-instruction",
+			Usage: "System instruction sent with each prompt",
+		},
+		&cli.Float64Flag{
+			Name:        "temperature",
+			Usage:       "Sampling temperature; lower values give more consistent pairings",
+			DefaultText: "provider's",
+		},
+		&cli.Float64Flag{
+			Name:        "top-p",
+			Usage:       "Nucleus sampling probability",
+			DefaultText: "provider's",
+		},
+		&cli.IntFlag{
+			Name:        "top-k",
+			Usage:       "Sample from only the K most likely tokens",
+			DefaultText: "provider's",
+		},
+		&cli.IntFlag{
+			Name:        "max-tokens",
+			Usage:       "Most tokens the model may generate per response",
+			DefaultText: "provider's",
+		},
+		&cli.StringSliceFlag{
+			Name:  "stop",
+			Usage: "Sequence that ends the response (may be repeated)",
+		},
+		&cli.Int64Flag{
+			Name:        "seed",
+			Usage:       "Seed for repeatable sampling, where the provider supports it",
+			DefaultText: "none",
+		},
+	}
+}
+
+// GenerationOptions returns the options for the generation flags set on ctx
+// itself. Flags set on a parent context are left out, so that the global
+// flags and a command's own can be told apart.
+func GenerationOptions(ctx *cli.Context) []client.Option {
+	set := make(map[string]bool)
+	for _, name := range ctx.LocalFlagNames() {
+		set[name] = true
+	}
+
+	var opts []client.Option
+	if set["system-instruction"] {
+		opts = append(opts, client.WithSystemInstruction(ctx.String("system-instruction")))
+	}
+	if set["temperature"] {
+		opts = append(opts, client.WithTemperature(ctx.Float64("temperature")))
+	}
+	if set["top-p"] {
+		opts = append(opts, client.WithTopP(ctx.Float64("top-p")))
+	}
+	if set["top-k"] {
+		opts = append(opts, client.WithTopK(ctx.Int("top-k")))
+	}
+	if set["max-tokens"] {
+		opts = append(opts, client.WithMaxTokens(ctx.Int("max-tokens")))
+	}
+	if set["stop"] {
+		opts = append(opts, client.WithStopSequences(ctx.StringSlice("stop")...))
+	}
+	if set["seed"] {
+		opts = append(opts, client.WithSeed(ctx.Int64("seed")))
+	}
+	return opts
+}
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	recipeCLI "github.com/kieranajp/pairings/internal/application/cli"
 	"github.com/kieranajp/pairings/internal/domain/recipe"
 	"github.com/kieranajp/pairings/internal/infrastructure/client"
@@ -14,6 +18,7 @@ type PairCommand struct {
 	llm           client.LLMClient
 	recipeService *recipe.Service
 	promptGen     prompt.Generator
+	translator    *recipe.Translator
 	log           logger.Logger
 }
 
@@ -37,6 +42,11 @@ func (c *PairCommand) WithPromptGen(promptGen prompt.Generator) *PairCommand {
 	return c
 }
 
+func (c *PairCommand) WithTranslator(translator *recipe.Translator) *PairCommand {
+	c.translator = translator
+	return c
+}
+
 func (c *PairCommand) WithLog(log logger.Logger) *PairCommand {
 	c.log = log
 	return c
@@ -49,27 +59,171 @@ func (c *PairCommand) Name() string {
 
 // Usage returns the usage description of the command
 func (c *PairCommand) Usage() string {
-	return "Get wine pairings for a recipe URL"
+	return "Get wine pairings for a recipe URL or file"
 }
 
 // Flags returns the command's flags
 func (c *PairCommand) Flags() []cli.Flag {
-	return []cli.Flag{
+	return append([]cli.Flag{
 		&cli.StringFlag{
-			Name:     "recipe",
-			Usage:    "Recipe URL",
-			Required: true,
+			Name:  "recipe",
+			Usage: "Recipe URL, or - to read the recipe from stdin",
 		},
-	}
+		&cli.StringFlag{
+			Name:  "recipe-file",
+			Usage: "Recipe file (HTML, JSON-LD, Markdown or plain text)",
+		},
+		&cli.StringFlag{
+			Name:  "import",
+			Usage: "Recipe manager export (Paprika, Mealie, Tandoor or Cooklang)",
+		},
+		&cli.StringFlag{
+			Name:    "recipe-title",
+			Aliases: []string{"select"},
+			Usage:   "Title of the recipe to pair, when the page or archive has several",
+		},
+		&cli.IntFlag{
+			Name:  "recipe-index",
+			Usage: "Position of the recipe to pair (starting at 1), when the page or archive has several",
+		},
+		&cli.BoolFlag{
+			Name:  "all-recipes",
+			Usage: "Pair every recipe on the page or in the archive",
+		},
+		&cli.BoolFlag{
+			Name:  "list-recipes",
+			Usage: "List the recipes on the page or in the archive without pairing",
+		},
+		&cli.BoolFlag{
+			Name:    "translate",
+			Usage:   "Translate recipes that aren't in English before pairing (the title is kept)",
+			EnvVars: []string{"PAIRINGS_TRANSLATE"},
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Output format: text or json",
+			Value:   recipeCLI.OutputText,
+		},
+		&cli.StringFlag{
+			Name:    "site-rules",
+			Usage:   "YAML file of per-site CSS selectors for recipe extraction",
+			EnvVars: []string{"PAIRINGS_SITE_RULES"},
+		},
+		&cli.DurationFlag{
+			Name:  "fetch-timeout",
+			Usage: "Timeout for fetching recipe pages",
+			Value: 30 * time.Second,
+		},
+		&cli.StringFlag{
+			Name:    "user-agent",
+			Usage:   "User agent sent when fetching recipe pages",
+			EnvVars: []string{"PAIRINGS_USER_AGENT"},
+		},
+		&cli.Int64Flag{
+			Name:  "max-page-size",
+			Usage: "Maximum size of a recipe page in bytes",
+			Value: 10 << 20,
+		},
+		&cli.IntFlag{
+			Name:  "max-redirects",
+			Usage: "Maximum number of redirects to follow when fetching recipe pages (-1 disables redirects)",
+			Value: 10,
+		},
+		&cli.StringSliceFlag{
+			Name:    "allow-host",
+			Usage:   "Host, IP address or CIDR range on a private network that recipes may be fetched from (repeatable)",
+			EnvVars: []string{"PAIRINGS_ALLOW_HOSTS"},
+		},
+		&cli.BoolFlag{
+			Name:  "ignore-robots",
+			Usage: "Fetch recipe pages even when the site's robots.txt disallows it",
+		},
+		&cli.DurationFlag{
+			Name:  "host-interval",
+			Usage: "Minimum time between requests to the same site (-1s disables)",
+			Value: time.Second,
+		},
+		&cli.IntFlag{
+			Name:  "host-concurrency",
+			Usage: "Maximum parallel requests to the same site",
+			Value: 2,
+		},
+		&cli.BoolFlag{
+			Name:  "no-cache",
+			Usage: "Fetch and extract the recipe afresh, bypassing the cache",
+		},
+		&cli.BoolFlag{
+			Name:  "no-stream",
+			Usage: "Show the pairings once they are complete rather than as they are generated",
+		},
+	}, GenerationFlags()...)
 }
 
 // Action returns a function that will be executed when the command is run
 func (c *PairCommand) Action(ctx *cli.Context) error {
 	handler := recipeCLI.NewRecipeHandler(
-		c.llm,
+		client.NewOptionsDecorator(c.llm, GenerationOptions(ctx)...),
 		c.recipeService,
 		c.promptGen,
 		c.log,
 	)
-	return handler.Handle(ctx.Context, ctx.String("recipe"))
+
+	switch output := ctx.String("output"); output {
+	case recipeCLI.OutputText, recipeCLI.OutputJSON:
+		handler.WithOutput(output)
+	default:
+		return fmt.Errorf("unknown output format %q, use text or json", output)
+	}
+	if ctx.Bool("translate") {
+		handler.WithTranslator(c.translator)
+	}
+	handler.WithStreaming(!ctx.Bool("no-stream"))
+	if isTerminal(os.Stderr) {
+		handler.WithProgress(os.Stderr)
+	}
+
+	recipeURL, recipeFile, importFile := ctx.String("recipe"), ctx.String("recipe-file"), ctx.String("import")
+	set := 0
+	for _, v := range []string{recipeURL, recipeFile, importFile} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of --recipe, --recipe-file or --import is required")
+	}
+
+	choices := 0
+	if ctx.String("recipe-title") != "" {
+		choices++
+	}
+	if ctx.Int("recipe-index") != 0 {
+		choices++
+	}
+	if ctx.Bool("all-recipes") {
+		choices++
+	}
+	if choices > 1 {
+		return fmt.Errorf("only one of --recipe-title, --recipe-index or --all-recipes may be set")
+	}
+	if ctx.Int("recipe-index") < 0 {
+		return fmt.Errorf("--recipe-index starts at 1")
+	}
+
+	source := recipeCLI.RecipeSource{
+		URL:    recipeURL,
+		File:   recipeFile,
+		Import: importFile,
+		Select: ctx.String("recipe-title"),
+		Index:  ctx.Int("recipe-index"),
+		All:    ctx.Bool("all-recipes"),
+		List:   ctx.Bool("list-recipes"),
+		Stdin:  os.Stdin,
+	}
+	if recipeURL == "-" {
+		source.URL, source.File = "", "-"
+	}
+
+	return handler.Handle(ctx.Context, source)
 }
@@ -54,7 +54,7 @@ func (c *PreferencesCommand) Usage() string {
 
 // Flags returns the command's flags
 func (c *PreferencesCommand) Flags() []cli.Flag {
-	return []cli.Flag{
+	return append([]cli.Flag{
 		&cli.StringFlag{
 			Name:     "dish",
 			Usage:    "Name of the dish to pair with",
@@ -91,13 +91,18 @@ func (c *PreferencesCommand) Flags() []cli.Flag {
 			Name:  "occasion",
 			Usage: "Occasion context (e.g., dinner party, casual meal) (optional)",
 		},
-	}
+		&cli.BoolFlag{
+			Name:  "no-stream",
+			Usage: "Show the recommendations once they are complete rather than as they are generated",
+		},
+	}, GenerationFlags()...)
 }
 
 // Action returns a function that will be executed when the command is run
 func (c *PreferencesCommand) Action(ctx *cli.Context) error {
-	service := wine.NewService(c.llm, c.promptGen, c.log)
-	handler := appCLI.NewPreferencesHandler(service)
+	llm := client.NewOptionsDecorator(c.llm, GenerationOptions(ctx)...)
+	service := wine.NewService(llm, c.promptGen, c.log)
+	handler := appCLI.NewPreferencesHandler(service).WithStreaming(!ctx.Bool("no-stream"))
 	return handler.Handle(
 		ctx.Context,
 		ctx.String("dish"),
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	appCLI "github.com/kieranajp/pairings/internal/application/cli"
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
+	"github.com/urfave/cli/v2"
+)
+
+// UsageCommand implements the Command interface for reporting spend from the
+// usage ledger
+type UsageCommand struct {
+	ledger *usage.Ledger
+}
+
+// NewUsageCommand creates a new usage command
+func NewUsageCommand() *UsageCommand {
+	return &UsageCommand{}
+}
+
+func (c *UsageCommand) WithLedger(ledger *usage.Ledger) *UsageCommand {
+	c.ledger = ledger
+	return c
+}
+
+// Name returns the name of the command
+func (c *UsageCommand) Name() string {
+	return "usage"
+}
+
+// Usage returns the usage description of the command
+func (c *UsageCommand) Usage() string {
+	return "Show token usage and spend by month, model and command"
+}
+
+// Flags returns the command's flags
+func (c *UsageCommand) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "month",
+			Usage: "Only show this month, as YYYY-MM",
+		},
+	}
+}
+
+// Action returns a function that will be executed when the command is run
+func (c *UsageCommand) Action(ctx *cli.Context) error {
+	return appCLI.NewUsageHandler(c.ledger).Handle(ctx.String("month"))
+}
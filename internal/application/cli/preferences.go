@@ -2,23 +2,39 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/kieranajp/pairings/internal/domain/wine"
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
 )
 
 // PreferencesHandler handles the preferences command
 type PreferencesHandler struct {
 	service *wine.Service
+	stream  bool
+	stdout  io.Writer
 }
 
 // NewPreferencesHandler creates a new preferences handler
 func NewPreferencesHandler(service *wine.Service) *PreferencesHandler {
 	return &PreferencesHandler{
 		service: service,
+		stdout:  os.Stdout,
 	}
 }
 
+// WithStreaming shows the recommendations as the model generates them rather
+// than once they are complete. If they turn out to be invalid a warning
+// follows them, but as they have already been shown the command still
+// succeeds.
+func (h *PreferencesHandler) WithStreaming(enabled bool) *PreferencesHandler {
+	h.stream = enabled
+	return h
+}
+
 // Handle processes the preferences command
 func (h *PreferencesHandler) Handle(
 	ctx context.Context,
@@ -29,6 +45,11 @@ func (h *PreferencesHandler) Handle(
 	tastePreferences []string,
 	occasion string,
 ) error {
+	if h.stream {
+		fmt.Fprintln(h.stdout, "Wine Recommendations for:", dish)
+		h.service.WithStream(func(chunk string) { fmt.Fprint(h.stdout, chunk) })
+	}
+
 	// Get recommendations from service
 	recommendations, err := h.service.GetRecommendations(
 		ctx,
@@ -41,13 +62,23 @@ func (h *PreferencesHandler) Handle(
 		tastePreferences,
 		occasion,
 	)
+	if h.stream {
+		fmt.Fprintln(h.stdout)
+		if errors.Is(err, client.ErrInvalidResponse) {
+			// The recommendations have already been shown, so flag them
+			// rather than fail after the fact
+			fmt.Fprintln(h.stdout, "Warning: the recommendations above failed validation and may be incomplete or wrong")
+			return nil
+		}
+		return err
+	}
 	if err != nil {
 		return err
 	}
 
 	// Display results
-	fmt.Println("Wine Recommendations for:", dish)
-	fmt.Println(recommendations)
+	fmt.Fprintln(h.stdout, "Wine Recommendations for:", dish)
+	fmt.Fprintln(h.stdout, recommendations)
 
 	return nil
 }
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kieranajp/pairings/internal/domain/wine"
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
+	"github.com/kieranajp/pairings/internal/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferencesHandler_StreamedValidation(t *testing.T) {
+	llm := &streamingLLM{
+		chunks: []string{`[{"name":`, `"Rioja"}]`},
+		err:    fmt.Errorf("%w: missing price", client.ErrInvalidResponse),
+	}
+	var stdout bytes.Buffer
+	h := NewPreferencesHandler(wine.NewService(llm, stubPrompts{}, logger.New("error"))).WithStreaming(true)
+	h.stdout = &stdout
+
+	err := h.Handle(context.Background(), "Beef Stew", 1000, 3000, "GBP", "red", "", nil, "")
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `[{"name":"Rioja"}]`)
+	assert.Contains(t, stdout.String(), "Warning: the recommendations above failed validation")
+}
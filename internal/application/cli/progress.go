@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+)
+
+// spinnerFrames are drawn in turn, one per chunk received
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progress shows on a terminal how much of a response has arrived, for
+// output that can't be shown until it is complete
+type progress struct {
+	w     io.Writer
+	label string
+	size  int
+	count int
+}
+
+func newProgress(w io.Writer, label string) *progress {
+	p := &progress{w: w, label: label}
+	p.draw()
+	return p
+}
+
+// update records a chunk of the response and redraws the indicator
+func (p *progress) update(chunk string) {
+	p.size += len(chunk)
+	p.count++
+	p.draw()
+}
+
+// done clears the indicator from the line
+func (p *progress) done() {
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+func (p *progress) draw() {
+	fmt.Fprintf(p.w, "\r\033[K%s %s %d bytes", spinnerFrames[p.count%len(spinnerFrames)], p.label, p.size)
+}
@@ -2,19 +2,52 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/kieranajp/pairings/internal/domain/recipe"
+	"github.com/kieranajp/pairings/internal/domain/wine"
 	"github.com/kieranajp/pairings/internal/infrastructure/client"
 	"github.com/kieranajp/pairings/internal/infrastructure/logger"
 	"github.com/kieranajp/pairings/internal/infrastructure/prompt"
 )
 
+// RecipeSource describes where to read the recipe from. Exactly one of URL,
+// File and Import should be set; a File of "-" reads from Stdin.
+//
+// Pages and archives can hold several recipes. Select picks one by title and
+// Index by position (starting at 1); All pairs every recipe and List just
+// prints their titles.
+type RecipeSource struct {
+	URL    string
+	File   string
+	Import string
+	Select string
+	Index  int
+	All    bool
+	List   bool
+	Stdin  io.Reader
+}
+
+// Output formats for the pairings
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
 type RecipeHandler struct {
 	llm           client.LLMClient
 	recipeService *recipe.Service
 	promptGen     prompt.Generator
 	logger        logger.Logger
+	translator    *recipe.Translator
+	output        string
+	stream        bool
+	stdout        io.Writer
+	progress      io.Writer
 }
 
 func NewRecipeHandler(
@@ -28,38 +61,250 @@ func NewRecipeHandler(
 		recipeService: recipeService,
 		promptGen:     promptGen,
 		logger:        logger,
+		output:        OutputText,
+		stdout:        os.Stdout,
 	}
 }
 
-func (h *RecipeHandler) Handle(ctx context.Context, url string) error {
-	h.logger.Info().Str("url", url).Msg("Getting wine pairings")
+// WithTranslator translates recipes that aren't in English before pairing
+func (h *RecipeHandler) WithTranslator(translator *recipe.Translator) *RecipeHandler {
+	h.translator = translator
+	return h
+}
+
+// WithOutput sets the output format, OutputText or OutputJSON
+func (h *RecipeHandler) WithOutput(format string) *RecipeHandler {
+	h.output = format
+	return h
+}
+
+// WithStreaming shows text output as the model generates it rather than once
+// it is complete. The pairings are still validated when they are complete;
+// if they turn out to be invalid a warning follows them, but as they have
+// already been shown the command still succeeds.
+func (h *RecipeHandler) WithStreaming(enabled bool) *RecipeHandler {
+	h.stream = enabled
+	return h
+}
+
+// WithProgress shows a progress indicator on w while waiting for JSON output,
+// which can't be shown until it is complete. w should be a terminal.
+func (h *RecipeHandler) WithProgress(w io.Writer) *RecipeHandler {
+	h.progress = w
+	return h
+}
+
+func (h *RecipeHandler) Handle(ctx context.Context, source RecipeSource) error {
+	h.logger.Info().
+		Str("url", source.URL).
+		Str("file", source.File).
+		Str("import", source.Import).
+		Msg("Getting wine pairings")
 
 	// Get recipe details
-	r, err := h.recipeService.GetRecipe(ctx, url)
+	recipes, err := h.loadRecipes(ctx, source)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to get recipe")
 		return fmt.Errorf("failed to get recipe: %w", err)
 	}
-	h.logger.Info().Str("title", r.Title).Msg("Got recipe details")
+
+	if source.List {
+		if h.output == OutputJSON {
+			return writeJSON(h.stdout, newRecipeListJSON(recipes))
+		}
+		for i, r := range recipes {
+			fmt.Fprintf(h.stdout, "%d. %s\n", i+1, r.Title)
+		}
+		return nil
+	}
+
+	chosen, err := chooseRecipes(recipes, source)
+	if err != nil {
+		return err
+	}
+
+	var results []pairingJSON
+	for i, r := range chosen {
+		r = h.translate(ctx, r)
+
+		if h.output == OutputJSON {
+			pairings, err := h.pairWithProgress(ctx, r)
+			if err != nil {
+				return err
+			}
+			results = append(results, newPairingJSON(r, pairings))
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(h.stdout)
+		}
+		if h.stream {
+			h.printHeader(r)
+			_, err := h.pair(ctx, r, func(chunk string) { fmt.Fprint(h.stdout, chunk) })
+			fmt.Fprintln(h.stdout)
+			if errors.Is(err, client.ErrInvalidResponse) {
+				// The pairings have already been shown, so flag them rather
+				// than fail after the fact
+				h.logger.Warn().Err(err).Msg("Streamed pairings failed validation")
+				fmt.Fprintln(h.stdout, "Warning: the pairings above failed validation and may be incomplete or wrong")
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		pairings, err := h.pair(ctx, r, nil)
+		if err != nil {
+			return err
+		}
+		h.printHeader(r)
+		fmt.Fprintln(h.stdout, pairings)
+	}
+
+	if h.output == OutputJSON {
+		// A single recipe is written as an object, several as an array
+		if len(results) == 1 {
+			return writeJSON(h.stdout, results[0])
+		}
+		return writeJSON(h.stdout, results)
+	}
+	return nil
+}
+
+// translate returns the recipe in English if translation is enabled. The
+// pairing still goes ahead with the original if translation fails.
+func (h *RecipeHandler) translate(ctx context.Context, r *recipe.Recipe) *recipe.Recipe {
+	if h.translator == nil {
+		return r
+	}
+
+	translated, err := h.translator.Translate(ctx, r)
+	if err != nil {
+		h.logger.Warn().Err(err).Str("language", r.Language).Msg("Failed to translate recipe, pairing it untranslated")
+		return r
+	}
+	if translated.TranslatedFrom != "" {
+		h.logger.Info().Str("language", translated.TranslatedFrom).Msg("Translated recipe")
+	}
+	return translated
+}
+
+// pairWithProgress gets the wine pairings for a recipe whose output has to
+// wait until they are complete, showing their progress if enabled
+func (h *RecipeHandler) pairWithProgress(ctx context.Context, r *recipe.Recipe) (string, error) {
+	if h.progress == nil || !h.stream {
+		return h.pair(ctx, r, nil)
+	}
+
+	p := newProgress(h.progress, "Pairing "+r.Title)
+	defer p.done()
+	return h.pair(ctx, r, p.update)
+}
+
+// pair gets the wine pairings for a single recipe. If onChunk is set the
+// response is streamed to it as it is generated.
+func (h *RecipeHandler) pair(ctx context.Context, r *recipe.Recipe, onChunk func(chunk string)) (string, error) {
+	h.logger.Info().Str("title", r.Title).Bool("llm_extracted", r.LLMExtracted).Msg("Got recipe details")
 
 	// Generate prompt
 	prompt, err := h.promptGen.GenerateWinePairingPrompt(r)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to generate prompt")
-		return fmt.Errorf("failed to generate prompt: %w", err)
+		return "", fmt.Errorf("failed to generate prompt: %w", err)
 	}
 	h.logger.Debug().Str("prompt", prompt).Msg("Generated prompt")
 
 	// Get wine pairings from LLM
-	pairings, err := h.llm.Complete(ctx, prompt)
+	var pairings string
+	if onChunk != nil {
+		pairings, err = client.Stream(ctx, h.llm, prompt, onChunk)
+	} else {
+		pairings, err = h.llm.Complete(ctx, prompt)
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to get pairings")
-		return fmt.Errorf("failed to get pairings: %w", err)
+		return "", fmt.Errorf("failed to get pairings: %w", err)
 	}
 
-	// Display results
-	fmt.Println("Wine Pairings for:", r.Title)
-	fmt.Println(pairings)
+	return pairings, nil
+}
 
-	return nil
+// printHeader displays what is known about a recipe ahead of its pairings
+func (h *RecipeHandler) printHeader(r *recipe.Recipe) {
+	fmt.Fprintln(h.stdout, "Wine Pairings for:", r.Title)
+	if details := formatDetails(r); details != "" {
+		fmt.Fprintln(h.stdout, details)
+	}
+	if bottles := wine.BottlesFor(r.Servings); bottles > 0 {
+		fmt.Fprintf(h.stdout, "Suggested quantity: %d bottle(s) for %d people\n", bottles, r.Servings)
+	}
+	fmt.Fprintln(h.stdout, "Flavour profile:", r.FormatFlavorProfile())
+	if r.TranslatedFrom != "" {
+		fmt.Fprintf(h.stdout, "Note: the recipe was translated from %s for pairing\n", recipe.LanguageName(r.TranslatedFrom))
+	}
+	if r.LLMExtracted {
+		fmt.Fprintln(h.stdout, "Note: this page had no structured recipe data, so the recipe was extracted by the LLM and may be less accurate")
+	}
+}
+
+// chooseRecipes picks which of the recipes found to pair
+func chooseRecipes(recipes []*recipe.Recipe, source RecipeSource) ([]*recipe.Recipe, error) {
+	switch {
+	case source.All:
+		return recipes, nil
+	case source.Index > 0:
+		if source.Index > len(recipes) {
+			return nil, fmt.Errorf("recipe index %d out of range, found %d recipes", source.Index, len(recipes))
+		}
+		return recipes[source.Index-1 : source.Index], nil
+	}
+
+	r, err := recipe.SelectRecipe(recipes, source.Select)
+	if err != nil {
+		return nil, err
+	}
+	return []*recipe.Recipe{r}, nil
+}
+
+// loadRecipes fetches or reads every recipe from the given source
+func (h *RecipeHandler) loadRecipes(ctx context.Context, source RecipeSource) ([]*recipe.Recipe, error) {
+	if source.Import != "" {
+		return recipe.ImportFile(source.Import)
+	}
+
+	switch source.File {
+	case "":
+		return h.recipeService.GetRecipes(ctx, source.URL)
+	case "-":
+		return h.recipeService.ReadRecipes(ctx, source.Stdin, "")
+	}
+
+	f, err := os.Open(source.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recipe file: %w", err)
+	}
+	defer f.Close()
+
+	return h.recipeService.ReadRecipes(ctx, f, source.File)
+}
+
+// formatDetails summarises the recipe's servings and timings on one line
+func formatDetails(r *recipe.Recipe) string {
+	var parts []string
+	if r.Servings > 0 {
+		parts = append(parts, fmt.Sprintf("Serves %d", r.Servings))
+	}
+	if d := recipe.FormatDuration(r.PrepTime); d != "" {
+		parts = append(parts, "Prep "+d)
+	}
+	if d := recipe.FormatDuration(r.CookTime); d != "" {
+		parts = append(parts, "Cook "+d)
+	}
+	if d := recipe.FormatDuration(r.Duration()); d != "" {
+		parts = append(parts, "Total "+d)
+	}
+	return strings.Join(parts, " · ")
 }
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/domain/recipe"
+)
+
+// pairingJSON is a recipe and its pairings as written by --output json
+type pairingJSON struct {
+	Recipe   recipeJSON      `json:"recipe"`
+	Pairings json.RawMessage `json:"pairings"`
+}
+
+// recipeJSON is the JSON form of a recipe. Durations are ISO 8601, as on
+// schema.org.
+type recipeJSON struct {
+	Title           string         `json:"title"`
+	Description     string         `json:"description,omitempty"`
+	Ingredients     []string       `json:"ingredients"`
+	Instructions    []string       `json:"instructions,omitempty"`
+	PrepTime        string         `json:"prep_time,omitempty"`
+	CookTime        string         `json:"cook_time,omitempty"`
+	TotalTime       string         `json:"total_time,omitempty"`
+	Yield           string         `json:"yield,omitempty"`
+	Servings        int            `json:"servings,omitempty"`
+	Cuisine         string         `json:"cuisine,omitempty"`
+	Language        string         `json:"language,omitempty"`
+	TranslatedFrom  string         `json:"translated_from,omitempty"`
+	Category        []string       `json:"category,omitempty"`
+	Keywords        []string       `json:"keywords,omitempty"`
+	CookingMethod   string         `json:"cooking_method,omitempty"`
+	SuitableForDiet []string       `json:"suitable_for_diet,omitempty"`
+	Nutrition       *nutritionJSON `json:"nutrition,omitempty"`
+	Images          []string       `json:"images,omitempty"`
+	FlavourProfile  string         `json:"flavour_profile"`
+	LLMExtracted    bool           `json:"llm_extracted"`
+	Provenance      provenanceJSON `json:"provenance"`
+}
+
+type nutritionJSON struct {
+	Calories     string `json:"calories,omitempty"`
+	Fat          string `json:"fat,omitempty"`
+	SaturatedFat string `json:"saturated_fat,omitempty"`
+	Carbohydrate string `json:"carbohydrate,omitempty"`
+	Sugar        string `json:"sugar,omitempty"`
+	Fiber        string `json:"fiber,omitempty"`
+	Protein      string `json:"protein,omitempty"`
+	Sodium       string `json:"sodium,omitempty"`
+}
+
+type provenanceJSON struct {
+	SourceURL    string     `json:"source_url,omitempty"`
+	CanonicalURL string     `json:"canonical_url,omitempty"`
+	AlternateURL string     `json:"alternate_url,omitempty"`
+	FetchedAt    *time.Time `json:"fetched_at,omitempty"`
+	Extractor    string     `json:"extractor,omitempty"`
+}
+
+// newPairingJSON pairs a recipe with the LLM's response, embedding the
+// response as JSON when it is valid and as a string otherwise
+func newPairingJSON(r *recipe.Recipe, pairings string) pairingJSON {
+	raw := json.RawMessage(pairings)
+	if !json.Valid(raw) {
+		raw, _ = json.Marshal(pairings)
+	}
+	return pairingJSON{Recipe: newRecipeJSON(r), Pairings: raw}
+}
+
+// newRecipeListJSON converts every recipe for --list-recipes
+func newRecipeListJSON(recipes []*recipe.Recipe) []recipeJSON {
+	list := make([]recipeJSON, len(recipes))
+	for i, r := range recipes {
+		list[i] = newRecipeJSON(r)
+	}
+	return list
+}
+
+func newRecipeJSON(r *recipe.Recipe) recipeJSON {
+	out := recipeJSON{
+		Title:           r.Title,
+		Description:     r.Description,
+		Ingredients:     r.Ingredients,
+		Instructions:    r.Instructions,
+		PrepTime:        recipe.FormatISODuration(r.PrepTime),
+		CookTime:        recipe.FormatISODuration(r.CookTime),
+		TotalTime:       recipe.FormatISODuration(r.TotalTime),
+		Yield:           r.Yield,
+		Servings:        r.Servings,
+		Cuisine:         r.Cuisine,
+		Language:        r.Language,
+		TranslatedFrom:  r.TranslatedFrom,
+		Category:        r.Category,
+		Keywords:        r.Keywords,
+		CookingMethod:   r.CookingMethod,
+		SuitableForDiet: r.SuitableForDiet,
+		Images:          r.Images,
+		FlavourProfile:  r.FormatFlavorProfile(),
+		LLMExtracted:    r.LLMExtracted,
+		Provenance: provenanceJSON{
+			SourceURL:    r.Provenance.SourceURL,
+			CanonicalURL: r.Provenance.CanonicalURL,
+			AlternateURL: r.Provenance.AlternateURL,
+			Extractor:    r.Provenance.Extractor,
+		},
+	}
+	if !r.Nutrition.IsZero() {
+		n := nutritionJSON(r.Nutrition)
+		out.Nutrition = &n
+	}
+	if !r.Provenance.FetchedAt.IsZero() {
+		fetchedAt := r.Provenance.FetchedAt.UTC()
+		out.Provenance.FetchedAt = &fetchedAt
+	}
+	return out
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
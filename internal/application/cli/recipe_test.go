@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kieranajp/pairings/internal/domain/recipe"
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
+	"github.com/kieranajp/pairings/internal/infrastructure/logger"
+	"github.com/kieranajp/pairings/internal/infrastructure/prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingLLM streams its chunks, then fails with err
+type streamingLLM struct {
+	chunks []string
+	err    error
+}
+
+func (s *streamingLLM) Complete(ctx context.Context, prompt string, opts ...client.Option) (string, error) {
+	return "", errors.New("not streamed")
+}
+
+func (s *streamingLLM) Stream(ctx context.Context, prompt string, onChunk func(chunk string), opts ...client.Option) (string, error) {
+	var response string
+	for _, chunk := range s.chunks {
+		onChunk(chunk)
+		response += chunk
+	}
+	if s.err != nil {
+		return "", s.err
+	}
+	return response, nil
+}
+
+// stubPrompts returns a fixed prompt for every request
+type stubPrompts struct {
+	prompt.Generator
+}
+
+func (stubPrompts) GenerateWinePairingPrompt(r *recipe.Recipe) (string, error) {
+	return "pair " + r.Title, nil
+}
+
+func (stubPrompts) GenerateWineRecommendationPrompt(dish, budgetMin, budgetMax, currency, styleStr, preferencesStr, occasionStr string) (string, error) {
+	return "recommend for " + dish, nil
+}
+
+func TestRecipeHandler_StreamedValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Stew.cook")
+	require.NoError(t, os.WriteFile(path, []byte("Braise @beef{1%kg} for ~{3%hours}.\n"), 0o644))
+
+	tests := []struct {
+		name        string
+		err         error
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "valid"},
+		{name: "invalid is shown with a warning", err: fmt.Errorf("%w: missing name", client.ErrInvalidResponse), wantWarning: true},
+		{name: "other errors fail", err: errors.New("connection reset"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			llm := &streamingLLM{chunks: []string{`[{"name":`, `"Rioja"}]`}, err: tt.err}
+			h := NewRecipeHandler(llm, recipe.NewService(), stubPrompts{}, logger.New("error")).WithStreaming(true)
+			h.stdout = &stdout
+
+			err := h.Handle(context.Background(), RecipeSource{Import: path})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Contains(t, stdout.String(), `[{"name":"Rioja"}]`)
+			if tt.wantWarning {
+				assert.Contains(t, stdout.String(), "Warning: the pairings above failed validation")
+			} else {
+				assert.NotContains(t, stdout.String(), "Warning")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
+)
+
+// UsageHandler handles the usage command
+type UsageHandler struct {
+	ledger *usage.Ledger
+	stdout io.Writer
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(ledger *usage.Ledger) *UsageHandler {
+	return &UsageHandler{
+		ledger: ledger,
+		stdout: os.Stdout,
+	}
+}
+
+// Handle prints the spend recorded in the ledger by month, model and
+// command. If month is set, as YYYY-MM, only that month is shown.
+func (h *UsageHandler) Handle(month string) error {
+	if month != "" {
+		if _, err := time.Parse("2006-01", month); err != nil {
+			return fmt.Errorf("invalid month %q, use YYYY-MM", month)
+		}
+	}
+
+	entries, err := h.ledger.Entries()
+	if err != nil {
+		return err
+	}
+
+	var chosen []usage.Entry
+	for _, e := range entries {
+		if month == "" || e.Month == month {
+			chosen = append(chosen, e)
+		}
+	}
+	if len(chosen) == 0 {
+		fmt.Fprintf(h.stdout, "No usage recorded in %s\n", h.ledger.Path())
+		return nil
+	}
+
+	w := tabwriter.NewWriter(h.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MONTH\tMODEL\tCOMMAND\tCALLS\tPROMPT\tCOMPLETION\tTOTAL\tCOST")
+	total, unpriced := 0.0, false
+	for _, e := range usage.Summarise(chosen) {
+		cost := "unpriced"
+		if e.CostUSD != nil {
+			cost = formatCost(*e.CostUSD)
+			total += *e.CostUSD
+		} else {
+			unpriced = true
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+			e.Month, e.Model, e.Command, e.Calls, e.Prompt, e.Completion, e.Total, cost)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(h.stdout, "\nTotal cost: %s", formatCost(total))
+	if unpriced {
+		fmt.Fprint(h.stdout, " (excluding unpriced models)")
+	}
+	fmt.Fprintln(h.stdout)
+	return nil
+}
+
+// PrintRunUsage writes the tokens used by each model during a run, and what
+// they cost
+func PrintRunUsage(w io.Writer, summary []usage.ModelUsage) {
+	for _, m := range summary {
+		cost := "unpriced"
+		if m.Priced {
+			cost = formatCost(m.Cost)
+		}
+		fmt.Fprintf(w, "Usage: %s: %d call(s), %d prompt + %d completion = %d tokens, %s\n",
+			m.Model, m.Calls, m.Tokens.Prompt, m.Tokens.Completion, m.Tokens.Total, cost)
+	}
+}
+
+// formatCost shows a cost in dollars. Single runs cost fractions of a cent,
+// so it keeps four decimal places.
+func formatCost(cost float64) string {
+	return fmt.Sprintf("$%.4f", cost)
+}
@@ -0,0 +1,113 @@
+package recipe
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/kieranajp/pairings/internal/infrastructure/fetcher"
+)
+
+// extractAlternates tries the other versions of a page that no extractor
+// could handle, returning the recipes from the first that has any along with
+// that version of the page. Alternates that fail to fetch are skipped.
+func (s *Service) extractAlternates(ctx context.Context, doc *goquery.Document, pageURL *url.URL) ([]*Recipe, *fetcher.Page, error) {
+	for _, alternate := range alternateURLs(doc, pageURL) {
+		page, err := s.fetcher.Fetch(ctx, alternate.String(), nil)
+		if err != nil {
+			continue
+		}
+
+		altDoc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+		if err != nil {
+			continue
+		}
+
+		if recipes, err := s.extractStructured(altDoc, page.URL); err == nil {
+			return recipes, page, nil
+		}
+	}
+	return nil, nil, ErrNoRecipe
+}
+
+// alternateURLs lists the other versions of a page that may carry the recipe
+// markup the page itself lacks, in the order they should be tried: its
+// canonical URL, its AMP version, then its print view
+func alternateURLs(doc *goquery.Document, pageURL *url.URL) []*url.URL {
+	var candidates []*url.URL
+	if canonical := canonicalURL(doc, pageURL); canonical != nil {
+		candidates = append(candidates, canonical)
+	}
+	if amp := linkURL(doc.Find("link[rel~='amphtml']").AttrOr("href", ""), pageURL); amp != nil {
+		candidates = append(candidates, amp)
+	}
+	candidates = append(candidates, printURLs(doc, pageURL)...)
+
+	// Pages usually name themselves as canonical, which isn't worth refetching
+	seen := map[string]bool{withoutFragment(pageURL): true}
+	var urls []*url.URL
+	for _, u := range candidates {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			continue
+		}
+		if key := withoutFragment(u); !seen[key] {
+			seen[key] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// printURLs returns the page's print view: the one it links to if it has a
+// print link, otherwise the /print/ and ?print=1 conventions used by recipe
+// plugins
+func printURLs(doc *goquery.Document, pageURL *url.URL) []*url.URL {
+	var linked *url.URL
+	doc.Find("a[href]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		href := s.AttrOr("href", "")
+		if strings.Contains(href, "/print/") || strings.Contains(href, "print=1") {
+			linked = linkURL(href, pageURL)
+		}
+		return linked == nil
+	})
+	if linked != nil {
+		return []*url.URL{linked}
+	}
+
+	path := *pageURL
+	path.Path = strings.TrimSuffix(pageURL.Path, "/") + "/print/"
+	path.RawPath = ""
+	path.Fragment = ""
+
+	query := *pageURL
+	values := query.Query()
+	values.Set("print", "1")
+	query.RawQuery = values.Encode()
+	query.Fragment = ""
+
+	return []*url.URL{&path, &query}
+}
+
+// linkURL resolves an href against the page URL, returning nil if it is empty
+// or invalid
+func linkURL(href string, pageURL *url.URL) *url.URL {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return nil
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil
+	}
+	return pageURL.ResolveReference(u)
+}
+
+// withoutFragment returns the URL as a string without its #fragment
+func withoutFragment(u *url.URL) string {
+	c := *u
+	c.Fragment = ""
+	c.RawFragment = ""
+	return c.String()
+}
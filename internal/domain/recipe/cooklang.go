@@ -0,0 +1,124 @@
+package recipe
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// @ingredient{quantity%unit}, where multi-word names need the braces
+	cooklangIngredient = regexp.MustCompile(`@([^@#~{}\n]+?)\{([^}]*)\}|@([\p{L}\p{N}_-]+)`)
+	cooklangCookware   = regexp.MustCompile(`#([^@#~{}\n]+?)\{[^}]*\}|#([\p{L}\p{N}_-]+)`)
+	cooklangTimer      = regexp.MustCompile(`~([^@#~{}\n]*)\{([^}]*)\}`)
+	cooklangComment    = regexp.MustCompile(`--.*$|\[-.*?-\]`)
+	cooklangMetadata   = regexp.MustCompile(`^>>\s*([^:]+):\s*(.*)$`)
+)
+
+// parseCooklang reads a Cooklang (.cook) recipe. Ingredients are marked up
+// inline in the steps, so the ingredient list is built from the steps.
+// name is used as the title if the file has no title metadata.
+func parseCooklang(name, text string) *Recipe {
+	r := &Recipe{Title: name}
+	metadata := map[string]string{}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	// Newer Cooklang files use YAML front matter instead of >> lines
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				for _, line := range lines[1:i] {
+					if key, value, ok := strings.Cut(line, ":"); ok {
+						metadata[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"'`)
+					}
+				}
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+
+	var step []string
+	flush := func() {
+		if len(step) > 0 {
+			r.Instructions = append(r.Instructions, cleanText(strings.Join(step, " ")))
+			step = nil
+		}
+	}
+
+	for _, line := range lines {
+		if m := cooklangMetadata.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			metadata[strings.ToLower(strings.TrimSpace(m[1]))] = strings.TrimSpace(m[2])
+			continue
+		}
+
+		line = strings.TrimSpace(cooklangComment.ReplaceAllString(line, ""))
+		if line == "" {
+			flush()
+			continue
+		}
+
+		for _, m := range cooklangIngredient.FindAllStringSubmatch(line, -1) {
+			r.Ingredients = append(r.Ingredients, cooklangIngredientLine(m))
+		}
+		step = append(step, cooklangStepText(line))
+	}
+	flush()
+
+	if title := metadata["title"]; title != "" {
+		r.Title = title
+	}
+	for _, key := range []string{"servings", "serves", "yield"} {
+		if v := metadata[key]; v != "" {
+			r.Yield = v
+			r.Servings = ParseServings(v)
+			break
+		}
+	}
+	r.PrepTime = ParseDuration(metadata["prep time"])
+	r.CookTime = ParseDuration(metadata["cook time"])
+	r.TotalTime = ParseDuration(metadata["time"])
+	r.Cuisine = metadata["cuisine"]
+	r.Description = metadata["description"]
+	r.Category = splitKeywords([]string{metadata["course"]})
+	// Front matter may give tags as a YAML flow sequence, e.g. [quick, pasta]
+	r.Keywords = splitKeywords([]string{strings.Trim(metadata["tags"], "[]")})
+	r.SuitableForDiet = splitKeywords([]string{metadata["diet"]})
+	r.Provenance.SourceURL = metadata["source"]
+
+	return r
+}
+
+// cooklangIngredientLine turns an ingredient match into a normal ingredient
+// line, e.g. "@beef shin{1.5%kg}" becomes "1.5 kg beef shin"
+func cooklangIngredientLine(m []string) string {
+	if m[3] != "" {
+		return m[3]
+	}
+	name := strings.TrimSpace(m[1])
+	quantity, unit, _ := strings.Cut(m[2], "%")
+	return cleanText(strings.Join([]string{quantity, unit, name}, " "))
+}
+
+// cooklangStepText strips the markup from a step, leaving readable text
+func cooklangStepText(line string) string {
+	line = cooklangIngredient.ReplaceAllStringFunc(line, func(s string) string {
+		m := cooklangIngredient.FindStringSubmatch(s)
+		if m[3] != "" {
+			return m[3]
+		}
+		return strings.TrimSpace(m[1])
+	})
+	line = cooklangCookware.ReplaceAllStringFunc(line, func(s string) string {
+		m := cooklangCookware.FindStringSubmatch(s)
+		if m[2] != "" {
+			return m[2]
+		}
+		return strings.TrimSpace(m[1])
+	})
+	return cooklangTimer.ReplaceAllStringFunc(line, func(s string) string {
+		m := cooklangTimer.FindStringSubmatch(s)
+		quantity, unit, _ := strings.Cut(m[2], "%")
+		return strings.TrimSpace(quantity + " " + unit)
+	})
+}
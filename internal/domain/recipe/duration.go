@@ -0,0 +1,126 @@
+package recipe
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	isoDuration  = regexp.MustCompile(`(?i)^P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+	textDuration = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?|½|¼|¾)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b`)
+	letterDigit  = regexp.MustCompile(`([a-zA-Z])(\d)`)
+	firstNumber  = regexp.MustCompile(`\d+`)
+)
+
+// ParseDuration reads a recipe time, either as an ISO 8601 duration such as
+// "PT1H30M" (the schema.org format) or as text such as "1 hour 30 mins".
+// Returns 0 if the value can't be understood.
+func ParseDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	if m := isoDuration.FindStringSubmatch(s); m != nil {
+		units := []time.Duration{
+			365 * 24 * time.Hour, // Y
+			30 * 24 * time.Hour,  // M
+			7 * 24 * time.Hour,   // W
+			24 * time.Hour,       // D
+			time.Hour,            // H
+			time.Minute,          // M
+			time.Second,          // S
+		}
+		var d time.Duration
+		for i, unit := range units {
+			d += scaleDuration(m[i+1], unit)
+		}
+		return d
+	}
+
+	// Split compact forms like "1h15m" so each unit is its own word
+	s = letterDigit.ReplaceAllString(s, "$1 $2")
+
+	var d time.Duration
+	for _, m := range textDuration.FindAllStringSubmatch(s, -1) {
+		var unit time.Duration
+		switch strings.ToLower(m[2])[0] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		case 's':
+			unit = time.Second
+		}
+		d += scaleDuration(m[1], unit)
+	}
+	return d
+}
+
+// scaleDuration multiplies unit by a possibly fractional number
+func scaleDuration(value string, unit time.Duration) time.Duration {
+	if value == "" {
+		return 0
+	}
+	var n float64
+	switch value {
+	case "½":
+		n = 0.5
+	case "¼":
+		n = 0.25
+	case "¾":
+		n = 0.75
+	default:
+		n, _ = strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	}
+	return time.Duration(n * float64(unit))
+}
+
+// ParseServings reads the number of servings from a recipe yield such as
+// "Serves 4-6" or "4 portions". Returns 0 if there is no number.
+func ParseServings(yield string) int {
+	n, err := strconv.Atoi(firstNumber.FindString(yield))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// FormatDuration formats a recipe time for display, e.g. "1h 30m"
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	d = d.Round(time.Minute)
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours == 0:
+		return strconv.Itoa(minutes) + "m"
+	case minutes == 0:
+		return strconv.Itoa(hours) + "h"
+	}
+	return strconv.Itoa(hours) + "h " + strconv.Itoa(minutes) + "m"
+}
+
+// FormatISODuration formats a duration as ISO 8601, e.g. "PT1H30M", the form
+// schema.org uses. Zero or negative durations return an empty string.
+func FormatISODuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	d = d.Round(time.Minute)
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+
+	s := "PT"
+	if hours > 0 {
+		s += strconv.Itoa(hours) + "H"
+	}
+	if minutes > 0 {
+		s += strconv.Itoa(minutes) + "M"
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{input: "PT1H30M", expected: 90 * time.Minute},
+		{input: "PT90M", expected: 90 * time.Minute},
+		{input: "P0DT2H", expected: 2 * time.Hour},
+		{input: "P1DT0H0M", expected: 24 * time.Hour},
+		{input: "PT1.5H", expected: 90 * time.Minute},
+		{input: "pt45m", expected: 45 * time.Minute},
+		{input: "1 hour 30 mins", expected: 90 * time.Minute},
+		{input: "45 minutes", expected: 45 * time.Minute},
+		{input: "2 hrs", expected: 2 * time.Hour},
+		{input: "1h15m", expected: 75 * time.Minute},
+		{input: "", expected: 0},
+		{input: "overnight", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseDuration(tt.input))
+		})
+	}
+}
+
+func TestParseServings(t *testing.T) {
+	assert.Equal(t, 4, ParseServings("4"))
+	assert.Equal(t, 4, ParseServings("Serves 4-6"))
+	assert.Equal(t, 8, ParseServings("8 portions"))
+	assert.Equal(t, 0, ParseServings("One large loaf"))
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "45m", FormatDuration(45*time.Minute))
+	assert.Equal(t, "2h", FormatDuration(2*time.Hour))
+	assert.Equal(t, "1h 30m", FormatDuration(90*time.Minute))
+	assert.Equal(t, "", FormatDuration(0))
+}
+
+func TestMicrodataExtractor_Times(t *testing.T) {
+	doc := newDoc(t, `
+		<div itemscope itemtype="http://schema.org/Recipe">
+			<h1 itemprop="name">Beef Stew</h1>
+			<meta itemprop="prepTime" content="PT20M">
+			<time itemprop="cookTime" datetime="PT3H">3 hours</time>
+			<span itemprop="totalTime">3 hours 20 minutes</span>
+			<span itemprop="recipeYield">Serves 6</span>
+		</div>
+	`)
+
+	got, err := (&MicrodataExtractor{}).Extract(doc, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Minute, got[0].Recipe.PrepTime)
+	assert.Equal(t, 3*time.Hour, got[0].Recipe.CookTime)
+	assert.Equal(t, 3*time.Hour+20*time.Minute, got[0].Recipe.TotalTime)
+	assert.Equal(t, 6, got[0].Recipe.Servings)
+}
@@ -0,0 +1,305 @@
+package recipe
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrNoRecipe is returned by extractors that found nothing on the page
+var ErrNoRecipe = errors.New("no recipe found")
+
+// Field names reported by extractors
+const (
+	FieldTitle        = "title"
+	FieldIngredients  = "ingredients"
+	FieldInstructions = "instructions"
+	FieldCookTime     = "cook_time"
+	FieldPrepTime     = "prep_time"
+	FieldTotalTime    = "total_time"
+	FieldYield        = "yield"
+	FieldCuisine      = "cuisine"
+
+	FieldDescription   = "description"
+	FieldCategory      = "category"
+	FieldKeywords      = "keywords"
+	FieldCookingMethod = "cooking_method"
+	FieldDiet          = "suitable_for_diet"
+	FieldNutrition     = "nutrition"
+	FieldImages        = "images"
+)
+
+// fieldWeights controls how much each field contributes to an extraction's
+// confidence. Ingredients and instructions matter most for pairing; fields
+// not listed are reported but don't affect confidence.
+var fieldWeights = map[string]float64{
+	FieldTitle:        0.20,
+	FieldIngredients:  0.35,
+	FieldInstructions: 0.25,
+	FieldCookTime:     0.05,
+	FieldPrepTime:     0.05,
+	FieldTotalTime:    0.03,
+	FieldYield:        0.03,
+	FieldCuisine:      0.04,
+}
+
+// Extraction is the result of running a single extractor over a page
+type Extraction struct {
+	Recipe     *Recipe
+	Extractor  string
+	Fields     []string
+	Confidence float64
+}
+
+// RecipeExtractor pulls a recipe out of a parsed page
+type RecipeExtractor interface {
+	// Name identifies the extractor in logs and extraction results
+	Name() string
+
+	// Extract returns every recipe it could find on the page, in page order,
+	// or ErrNoRecipe
+	Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error)
+}
+
+// siteSpecificExtractor is implemented by extractors whose rules were written
+// for the page's own site. Their results rank above the generic extractors
+// whatever their confidence, so a rule can correct structured data that is
+// complete but wrong.
+type siteSpecificExtractor interface {
+	siteSpecific()
+}
+
+// DefaultExtractors returns the standard extractor chain in priority order.
+// rules may be nil if no site-specific rules are configured.
+func DefaultExtractors(rules SiteRules) []RecipeExtractor {
+	return []RecipeExtractor{
+		&JSONLDExtractor{},
+		&MicrodataExtractor{},
+		&RDFaExtractor{},
+		&MicroformatsExtractor{},
+		NewSiteRulesExtractor(rules),
+		&HeuristicExtractor{},
+	}
+}
+
+// newExtraction scores a recipe found by an extractor. reliability scales the
+// score to reflect how trustworthy the extractor's source format is.
+func newExtraction(name string, r *Recipe, reliability float64) (*Extraction, error) {
+	if r == nil || r.Title == "" {
+		return nil, ErrNoRecipe
+	}
+
+	fields := presentFields(r)
+	var score float64
+	for _, f := range fields {
+		score += fieldWeights[f]
+	}
+
+	return &Extraction{
+		Recipe:     r,
+		Extractor:  name,
+		Fields:     fields,
+		Confidence: score * reliability,
+	}, nil
+}
+
+// newExtractions scores each of the recipes found by an extractor, dropping
+// any without a title
+func newExtractions(name string, recipes []*Recipe, reliability float64) ([]*Extraction, error) {
+	var results []*Extraction
+	for _, r := range recipes {
+		if result, err := newExtraction(name, r, reliability); err == nil {
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return nil, ErrNoRecipe
+	}
+	return results, nil
+}
+
+// single wraps the result of an extractor that finds at most one recipe
+func single(result *Extraction, err error) ([]*Extraction, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []*Extraction{result}, nil
+}
+
+// presentFields lists which fields of the recipe have been populated
+func presentFields(r *Recipe) []string {
+	var fields []string
+	if r.Title != "" {
+		fields = append(fields, FieldTitle)
+	}
+	if len(r.Ingredients) > 0 {
+		fields = append(fields, FieldIngredients)
+	}
+	if len(r.Instructions) > 0 {
+		fields = append(fields, FieldInstructions)
+	}
+	if r.CookTime > 0 {
+		fields = append(fields, FieldCookTime)
+	}
+	if r.PrepTime > 0 {
+		fields = append(fields, FieldPrepTime)
+	}
+	if r.TotalTime > 0 {
+		fields = append(fields, FieldTotalTime)
+	}
+	if r.Yield != "" {
+		fields = append(fields, FieldYield)
+	}
+	if r.Cuisine != "" {
+		fields = append(fields, FieldCuisine)
+	}
+	if r.Description != "" {
+		fields = append(fields, FieldDescription)
+	}
+	if len(r.Category) > 0 {
+		fields = append(fields, FieldCategory)
+	}
+	if len(r.Keywords) > 0 {
+		fields = append(fields, FieldKeywords)
+	}
+	if r.CookingMethod != "" {
+		fields = append(fields, FieldCookingMethod)
+	}
+	if len(r.SuitableForDiet) > 0 {
+		fields = append(fields, FieldDiet)
+	}
+	if !r.Nutrition.IsZero() {
+		fields = append(fields, FieldNutrition)
+	}
+	if len(r.Images) > 0 {
+		fields = append(fields, FieldImages)
+	}
+	return fields
+}
+
+// runExtractors runs every extractor over the page and returns each recipe
+// on it, with any gaps filled in from the less confident extractors. The
+// extractor with the single most confident result, or a site-specific one if
+// it found anything, decides how many recipes the page holds.
+func runExtractors(extractors []RecipeExtractor, doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	var siteGroups, groups [][]*Extraction
+	for _, e := range extractors {
+		results, err := e.Extract(doc, pageURL)
+		if err != nil || len(results) == 0 {
+			continue
+		}
+		if _, ok := e.(siteSpecificExtractor); ok {
+			siteGroups = append(siteGroups, results)
+			continue
+		}
+		groups = append(groups, results)
+	}
+
+	// Stable so that ties go to the extractor earlier in the chain
+	for _, g := range [][][]*Extraction{siteGroups, groups} {
+		sort.SliceStable(g, func(i, j int) bool {
+			return bestConfidence(g[i]) > bestConfidence(g[j])
+		})
+	}
+	groups = append(siteGroups, groups...)
+
+	if len(groups) == 0 {
+		return nil, ErrNoRecipe
+	}
+
+	primary := groups[0]
+	merged := make([]*Extraction, len(primary))
+	for i, best := range primary {
+		r := *best.Recipe
+		for _, other := range groups[1:] {
+			if match := matchingExtraction(other, r.Title, len(primary) == 1); match != nil {
+				mergeRecipe(&r, match.Recipe)
+			}
+		}
+
+		merged[i] = &Extraction{
+			Recipe:     &r,
+			Extractor:  best.Extractor,
+			Fields:     presentFields(&r),
+			Confidence: best.Confidence,
+		}
+	}
+	return merged, nil
+}
+
+// bestConfidence returns the highest confidence among an extractor's results
+func bestConfidence(results []*Extraction) float64 {
+	var best float64
+	for _, r := range results {
+		best = max(best, r.Confidence)
+	}
+	return best
+}
+
+// matchingExtraction finds the result describing the recipe titled title.
+// When both sides found a single recipe they're taken to be the same one, as
+// extractors often disagree on the title ("Stew" vs "Stew | Example.com").
+func matchingExtraction(results []*Extraction, title string, singleRecipe bool) *Extraction {
+	for _, r := range results {
+		if strings.EqualFold(cleanText(r.Recipe.Title), cleanText(title)) {
+			return r
+		}
+	}
+	if singleRecipe && len(results) == 1 {
+		return results[0]
+	}
+	return nil
+}
+
+// mergeRecipe fills any empty fields in dst from src
+func mergeRecipe(dst, src *Recipe) {
+	if dst.Title == "" {
+		dst.Title = src.Title
+	}
+	if len(dst.Ingredients) == 0 {
+		dst.Ingredients = src.Ingredients
+	}
+	if len(dst.Instructions) == 0 {
+		dst.Instructions = src.Instructions
+	}
+	if dst.CookTime == 0 {
+		dst.CookTime = src.CookTime
+	}
+	if dst.PrepTime == 0 {
+		dst.PrepTime = src.PrepTime
+	}
+	if dst.TotalTime == 0 {
+		dst.TotalTime = src.TotalTime
+	}
+	if dst.Yield == "" {
+		dst.Yield = src.Yield
+		dst.Servings = src.Servings
+	}
+	if dst.Cuisine == "" {
+		dst.Cuisine = src.Cuisine
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if len(dst.Category) == 0 {
+		dst.Category = src.Category
+	}
+	if len(dst.Keywords) == 0 {
+		dst.Keywords = src.Keywords
+	}
+	if dst.CookingMethod == "" {
+		dst.CookingMethod = src.CookingMethod
+	}
+	if len(dst.SuitableForDiet) == 0 {
+		dst.SuitableForDiet = src.SuitableForDiet
+	}
+	if dst.Nutrition.IsZero() {
+		dst.Nutrition = src.Nutrition
+	}
+	if len(dst.Images) == 0 {
+		dst.Images = src.Images
+	}
+}
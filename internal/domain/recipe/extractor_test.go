@@ -0,0 +1,288 @@
+package recipe
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubExtractor returns a fixed recipe
+type stubExtractor struct {
+	name        string
+	recipe      *Recipe
+	reliability float64
+}
+
+func (s *stubExtractor) Name() string {
+	return s.name
+}
+
+func (s *stubExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	return single(newExtraction(s.name, s.recipe, s.reliability))
+}
+
+func TestRunExtractors(t *testing.T) {
+	tests := []struct {
+		name          string
+		extractors    []RecipeExtractor
+		wantExtractor string
+		wantRecipe    *Recipe
+		wantErr       error
+	}{
+		{
+			name: "most complete result wins",
+			extractors: []RecipeExtractor{
+				&stubExtractor{name: "sparse", recipe: &Recipe{Title: "Stew"}, reliability: 1.0},
+				&stubExtractor{name: "full", recipe: &Recipe{
+					Title:        "Beef Stew",
+					Ingredients:  []string{"beef"},
+					Instructions: []string{"simmer"},
+				}, reliability: 0.9},
+			},
+			wantExtractor: "full",
+			wantRecipe: &Recipe{
+				Title:        "Beef Stew",
+				Ingredients:  []string{"beef"},
+				Instructions: []string{"simmer"},
+			},
+		},
+		{
+			name: "gaps are filled from weaker results",
+			extractors: []RecipeExtractor{
+				&stubExtractor{name: "best", recipe: &Recipe{
+					Title:       "Beef Stew",
+					Ingredients: []string{"beef"},
+				}, reliability: 1.0},
+				&stubExtractor{name: "weak", recipe: &Recipe{
+					Title:    "Stew | Example.com",
+					CookTime: 3 * time.Hour,
+				}, reliability: 0.5},
+			},
+			wantExtractor: "best",
+			wantRecipe: &Recipe{
+				Title:       "Beef Stew",
+				Ingredients: []string{"beef"},
+				CookTime:    3 * time.Hour,
+			},
+		},
+		{
+			name: "nothing found",
+			extractors: []RecipeExtractor{
+				&stubExtractor{name: "empty", recipe: nil, reliability: 1.0},
+			},
+			wantErr: ErrNoRecipe,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runExtractors(tt.extractors, newDoc(t, "<html></html>"), nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.wantExtractor, got[0].Extractor)
+			assert.Equal(t, tt.wantRecipe, got[0].Recipe)
+		})
+	}
+}
+
+func TestRunExtractors_MultipleRecipes(t *testing.T) {
+	doc := newDoc(t, `
+		<div itemscope itemtype="http://schema.org/Recipe">
+			<h2 itemprop="name">Cacio e Pepe</h2>
+			<span itemprop="recipeIngredient">200g spaghetti</span>
+			<meta itemprop="cookTime" content="PT15M">
+		</div>
+		<div itemscope itemtype="http://schema.org/Recipe">
+			<h2 itemprop="name">Puttanesca</h2>
+			<span itemprop="recipeIngredient">50g olives</span>
+		</div>
+		<script type="application/ld+json">[
+			{"@type": "Recipe", "name": "Cacio e Pepe", "recipeIngredient": ["200g spaghetti", "100g pecorino"], "recipeInstructions": "Toss."},
+			{"@type": "Recipe", "name": "Puttanesca", "recipeIngredient": ["50g olives", "2 anchovies"], "recipeInstructions": "Simmer."}
+		]</script>
+	`)
+
+	got, err := runExtractors(DefaultExtractors(nil), doc, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	// Each recipe keeps its own ingredients, with gaps filled from the
+	// matching microdata recipe only
+	assert.Equal(t, "Cacio e Pepe", got[0].Recipe.Title)
+	assert.Equal(t, []string{"200g spaghetti", "100g pecorino"}, got[0].Recipe.Ingredients)
+	assert.Equal(t, 15*time.Minute, got[0].Recipe.CookTime)
+	assert.Equal(t, "Puttanesca", got[1].Recipe.Title)
+	assert.Equal(t, []string{"50g olives", "2 anchovies"}, got[1].Recipe.Ingredients)
+	assert.Zero(t, got[1].Recipe.CookTime)
+}
+
+func TestSiteRulesExtractor(t *testing.T) {
+	rules, err := ParseSiteRules([]byte(`
+example.com:
+  title: h1.title
+  ingredients: ul.ingr li
+  instructions: ol.steps li
+  cook_time: .cook time
+`))
+	require.NoError(t, err)
+
+	doc := newDoc(t, `
+		<h1 class="title">Beef Stew</h1>
+		<ul class="ingr"><li>1 kg beef</li><li> 2  carrots </li></ul>
+		<ol class="steps"><li>Brown.</li><li>Simmer.</li></ol>
+		<span class="cook"><time datetime="PT3H">3 hours</time></span>
+	`)
+
+	extractor := NewSiteRulesExtractor(rules)
+
+	pageURL, _ := url.Parse("https://www.example.com/recipes/stew")
+	got, err := extractor.Extract(doc, pageURL)
+	require.NoError(t, err)
+	assert.Equal(t, &Recipe{
+		Title:        "Beef Stew",
+		Ingredients:  []string{"1 kg beef", "2 carrots"},
+		Instructions: []string{"Brown.", "Simmer."},
+		CookTime:     3 * time.Hour,
+	}, got[0].Recipe)
+
+	otherURL, _ := url.Parse("https://other.example.org/")
+	_, err = extractor.Extract(doc, otherURL)
+	assert.ErrorIs(t, err, ErrNoRecipe)
+
+	// The rule wins over complete but wrong JSON-LD, which fills in the rest
+	doc = newDoc(t, `
+		<h1 class="title">Beef Stew</h1>
+		<ul class="ingr"><li>1 kg beef</li></ul>
+		<script type="application/ld+json">{
+			"@type": "Recipe", "name": "Newsletter Signup", "recipeIngredient": ["your email"],
+			"recipeInstructions": "Subscribe.", "cookTime": "PT3H", "prepTime": "PT20M",
+			"recipeYield": "4", "recipeCuisine": "British"
+		}</script>
+	`)
+	results, err := runExtractors(DefaultExtractors(rules), doc, pageURL)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "site-rules", results[0].Extractor)
+	assert.Equal(t, "Beef Stew", results[0].Recipe.Title)
+	assert.Equal(t, []string{"1 kg beef"}, results[0].Recipe.Ingredients)
+	assert.Equal(t, "British", results[0].Recipe.Cuisine)
+}
+
+func TestParseSiteRules_InvalidSelector(t *testing.T) {
+	_, err := ParseSiteRules([]byte(`
+example.com:
+  title: h1.title
+  ingredients: ul.ingr li[
+`))
+	assert.ErrorContains(t, err, "ingredients selector")
+}
+
+func TestRDFaExtractor(t *testing.T) {
+	doc := newDoc(t, `
+		<div vocab="http://schema.org/" typeof="Recipe">
+			<h2 property="name">Ratatouille</h2>
+			<meta property="schema:cookTime" content="PT1H">
+			<ul>
+				<li property="recipeIngredient">1 aubergine</li>
+				<li property="recipeIngredient">2 courgettes</li>
+			</ul>
+			<p property="recipeInstructions">Stew the vegetables.</p>
+		</div>
+	`)
+
+	got, err := (&RDFaExtractor{}).Extract(doc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &Recipe{
+		Title:        "Ratatouille",
+		Ingredients:  []string{"1 aubergine", "2 courgettes"},
+		Instructions: []string{"Stew the vegetables."},
+		CookTime:     time.Hour,
+	}, got[0].Recipe)
+}
+
+func TestRDFaExtractor_NestedResources(t *testing.T) {
+	doc := newDoc(t, `
+		<div vocab="https://schema.org/" typeof="Recipe">
+			<h2 property="name">Coq au Vin</h2>
+			<div property="author" typeof="Person"><span property="name">Julia</span></div>
+			<span property="recipeIngredient">1 chicken</span>
+			<div property="recipeInstructions" typeof="HowToStep"><span property="text">Marinate overnight.</span></div>
+			<div property="recipeInstructions" typeof="HowToStep"><span property="text">Braise.</span></div>
+		</div>
+		<div vocab="https://schema.org/" typeof="Recipe">
+			<h2 property="name">Tarte Tatin</h2>
+		</div>
+	`)
+
+	got, err := (&RDFaExtractor{}).Extract(doc, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Coq au Vin", got[0].Recipe.Title)
+	assert.Equal(t, []string{"Marinate overnight.", "Braise."}, got[0].Recipe.Instructions)
+	assert.Equal(t, "Tarte Tatin", got[1].Recipe.Title)
+}
+
+func TestMicroformatsExtractor(t *testing.T) {
+	doc := newDoc(t, `
+		<article class="h-recipe">
+			<h1 class="p-name">Bangers &amp; Mash</h1>
+			<p class="p-author h-card"><span class="p-name">Sam</span></p>
+			<ul>
+				<li class="p-ingredient">6 pork sausages</li>
+				<li class="p-ingredient">1 kg potatoes</li>
+			</ul>
+			<p>Serves <data class="p-yield" value="4">four</data>,
+				takes <time class="dt-duration" datetime="PT45M">45 minutes</time></p>
+			<div class="e-instructions">
+				<ol><li>Boil the potatoes.</li><li>Fry the sausages.</li></ol>
+			</div>
+		</article>
+		<article class="h-recipe">
+			<h1 class="p-name">Onion Gravy</h1>
+			<span class="p-ingredient">2 onions</span>
+			<div class="e-instructions"><p>Soften the onions.</p><p>Add stock.</p></div>
+		</article>
+	`)
+
+	got, err := (&MicroformatsExtractor{}).Extract(doc, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, &Recipe{
+		Title:        "Bangers & Mash",
+		Ingredients:  []string{"6 pork sausages", "1 kg potatoes"},
+		Instructions: []string{"Boil the potatoes.", "Fry the sausages."},
+		TotalTime:    45 * time.Minute,
+		Yield:        "4",
+		Servings:     4,
+	}, got[0].Recipe)
+	assert.Equal(t, []string{"Soften the onions.", "Add stock."}, got[1].Recipe.Instructions)
+
+	_, err = (&MicroformatsExtractor{}).Extract(newDoc(t, `<div class="h-entry"><p class="p-name">A blog post</p></div>`), nil)
+	assert.ErrorIs(t, err, ErrNoRecipe)
+}
+
+func TestHeuristicExtractor(t *testing.T) {
+	doc := newDoc(t, `
+		<h1>Grandma's Meatballs</h1>
+		<div class="recipe-ingredients"><ul><li>500g pork mince</li><li>1 egg</li></ul></div>
+		<div id="method"><ol><li>Mix.</li><li>Roll and fry.</li></ol></div>
+	`)
+
+	got, err := (&HeuristicExtractor{}).Extract(doc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Grandma's Meatballs", got[0].Recipe.Title)
+	assert.Equal(t, []string{"500g pork mince", "1 egg"}, got[0].Recipe.Ingredients)
+	assert.Equal(t, []string{"Mix.", "Roll and fry."}, got[0].Recipe.Instructions)
+	assert.Less(t, got[0].Confidence, 0.6)
+
+	_, err = (&HeuristicExtractor{}).Extract(newDoc(t, "<h1>About us</h1>"), nil)
+	assert.ErrorIs(t, err, ErrNoRecipe)
+}
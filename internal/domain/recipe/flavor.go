@@ -0,0 +1,397 @@
+package recipe
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Flavour dimensions scored by FlavorProfile
+const (
+	DimensionFat        = "fat"
+	DimensionAcidity    = "acidity"
+	DimensionSalt       = "salt"
+	DimensionSweetness  = "sweetness"
+	DimensionHeat       = "heat"
+	DimensionUmami      = "umami"
+	DimensionBitterness = "bitterness"
+	DimensionIntensity  = "intensity"
+)
+
+// dimensions lists the flavour dimensions in display order
+var dimensions = []string{
+	DimensionFat,
+	DimensionAcidity,
+	DimensionSalt,
+	DimensionSweetness,
+	DimensionHeat,
+	DimensionUmami,
+	DimensionBitterness,
+	DimensionIntensity,
+}
+
+// CookingMethod is a cooking technique that changes how a dish tastes
+type CookingMethod string
+
+const (
+	Raw    CookingMethod = "raw"
+	Poach  CookingMethod = "poach"
+	Steam  CookingMethod = "steam"
+	Braise CookingMethod = "braise"
+	Roast  CookingMethod = "roast"
+	Grill  CookingMethod = "grill"
+	Fry    CookingMethod = "fry"
+	Bake   CookingMethod = "bake"
+	Smoke  CookingMethod = "smoke"
+	Cure   CookingMethod = "cure"
+)
+
+// taste holds how strongly an ingredient or method contributes to each
+// dimension, on a 0-3 scale
+type taste map[string]float64
+
+// ingredientTastes is the local ingredient attribute table. Keys are matched
+// against the parsed ingredient item, preferring one that names its head noun
+// and then the longest match.
+var ingredientTastes = map[string]taste{
+	// Meat and fish
+	"beef":       {DimensionFat: 2, DimensionUmami: 2, DimensionIntensity: 3},
+	"steak":      {DimensionFat: 2, DimensionUmami: 2, DimensionIntensity: 3},
+	"lamb":       {DimensionFat: 3, DimensionUmami: 2, DimensionIntensity: 3},
+	"venison":    {DimensionFat: 1, DimensionUmami: 2, DimensionIntensity: 3},
+	"pork":       {DimensionFat: 2, DimensionUmami: 1, DimensionIntensity: 2},
+	"pork belly": {DimensionFat: 3, DimensionUmami: 1, DimensionIntensity: 2},
+	"bacon":      {DimensionFat: 3, DimensionSalt: 3, DimensionUmami: 2, DimensionIntensity: 2},
+	"pancetta":   {DimensionFat: 3, DimensionSalt: 3, DimensionUmami: 2, DimensionIntensity: 2},
+	"chorizo":    {DimensionFat: 3, DimensionSalt: 2, DimensionHeat: 1, DimensionIntensity: 3},
+	"sausage":    {DimensionFat: 3, DimensionSalt: 2, DimensionIntensity: 2},
+	"ham":        {DimensionSalt: 3, DimensionUmami: 2, DimensionIntensity: 2},
+	"duck":       {DimensionFat: 3, DimensionUmami: 2, DimensionIntensity: 3},
+	"chicken":    {DimensionFat: 1, DimensionUmami: 1, DimensionIntensity: 1},
+	"turkey":     {DimensionFat: 1, DimensionUmami: 1, DimensionIntensity: 1},
+	"salmon":     {DimensionFat: 2, DimensionUmami: 1, DimensionIntensity: 2},
+	"tuna":       {DimensionFat: 1, DimensionUmami: 2, DimensionIntensity: 2},
+	"mackerel":   {DimensionFat: 3, DimensionUmami: 2, DimensionIntensity: 2},
+	"cod":        {DimensionIntensity: 1},
+	"white fish": {DimensionIntensity: 1},
+	"prawn":      {DimensionSweetness: 1, DimensionUmami: 1, DimensionIntensity: 1},
+	"shrimp":     {DimensionSweetness: 1, DimensionUmami: 1, DimensionIntensity: 1},
+	"scallop":    {DimensionSweetness: 2, DimensionUmami: 1, DimensionIntensity: 1},
+	"mussel":     {DimensionSalt: 1, DimensionUmami: 2, DimensionIntensity: 1},
+	"anchov":     {DimensionSalt: 3, DimensionUmami: 3, DimensionIntensity: 2},
+	"tofu":       {DimensionUmami: 1},
+	"egg":        {DimensionFat: 1, DimensionUmami: 1, DimensionIntensity: 1},
+
+	// Dairy and fats
+	"butter":        {DimensionFat: 3, DimensionIntensity: 1},
+	"cream":         {DimensionFat: 3, DimensionSweetness: 1, DimensionIntensity: 1},
+	"creme fraiche": {DimensionFat: 3, DimensionAcidity: 1, DimensionIntensity: 1},
+	"crème fraîche": {DimensionFat: 3, DimensionAcidity: 1, DimensionIntensity: 1},
+	"yogurt":        {DimensionFat: 1, DimensionAcidity: 2},
+	"yoghurt":       {DimensionFat: 1, DimensionAcidity: 2},
+	"milk":          {DimensionFat: 1, DimensionSweetness: 1},
+	"cheese":        {DimensionFat: 3, DimensionSalt: 2, DimensionUmami: 2, DimensionIntensity: 2},
+	"parmesan":      {DimensionFat: 2, DimensionSalt: 3, DimensionUmami: 3, DimensionIntensity: 2},
+	"feta":          {DimensionFat: 2, DimensionSalt: 3, DimensionAcidity: 1, DimensionIntensity: 2},
+	"goat":          {DimensionFat: 2, DimensionAcidity: 2, DimensionIntensity: 2},
+	"blue cheese":   {DimensionFat: 3, DimensionSalt: 3, DimensionUmami: 2, DimensionIntensity: 3},
+	"coconut milk":  {DimensionFat: 3, DimensionSweetness: 1, DimensionIntensity: 1},
+	"oil":           {DimensionFat: 2},
+
+	// Vegetables and fruit
+	"tomato":    {DimensionAcidity: 2, DimensionSweetness: 1, DimensionUmami: 2, DimensionIntensity: 1},
+	"mushroom":  {DimensionUmami: 3, DimensionIntensity: 2},
+	"onion":     {DimensionSweetness: 1, DimensionIntensity: 1},
+	"garlic":    {DimensionIntensity: 2},
+	"carrot":    {DimensionSweetness: 2},
+	"beetroot":  {DimensionSweetness: 2, DimensionIntensity: 1},
+	"squash":    {DimensionSweetness: 2, DimensionIntensity: 1},
+	"pumpkin":   {DimensionSweetness: 2, DimensionIntensity: 1},
+	"potato":    {},
+	"rice":      {},
+	"pasta":     {},
+	"noodle":    {},
+	"bread":     {},
+	"flour":     {},
+	"pea":       {DimensionSweetness: 1},
+	"sweetcorn": {DimensionSweetness: 2},
+	"spinach":   {DimensionBitterness: 1},
+	"kale":      {DimensionBitterness: 2, DimensionIntensity: 1},
+	"radicchio": {DimensionBitterness: 3, DimensionIntensity: 1},
+	"chicory":   {DimensionBitterness: 3, DimensionIntensity: 1},
+	"endive":    {DimensionBitterness: 2},
+	"rocket":    {DimensionBitterness: 2, DimensionIntensity: 1},
+	"arugula":   {DimensionBitterness: 2, DimensionIntensity: 1},
+	"aubergine": {DimensionBitterness: 1},
+	"eggplant":  {DimensionBitterness: 1},
+	"olive":     {DimensionSalt: 2, DimensionBitterness: 1, DimensionIntensity: 2},
+	"caper":     {DimensionSalt: 3, DimensionAcidity: 2, DimensionIntensity: 2},
+	"lemon":     {DimensionAcidity: 3},
+	"lime":      {DimensionAcidity: 3},
+	"orange":    {DimensionAcidity: 1, DimensionSweetness: 2},
+	"apple":     {DimensionAcidity: 1, DimensionSweetness: 2},
+	"pear":      {DimensionSweetness: 2},
+	"pineapple": {DimensionAcidity: 2, DimensionSweetness: 3},
+	"mango":     {DimensionSweetness: 3},
+	"date":      {DimensionSweetness: 3},
+	"raisin":    {DimensionSweetness: 3},
+	"berries":   {DimensionAcidity: 2, DimensionSweetness: 2},
+
+	// Heat and spice
+	"chilli":         {DimensionHeat: 3, DimensionIntensity: 2},
+	"chili":          {DimensionHeat: 3, DimensionIntensity: 2},
+	"jalapeño":       {DimensionHeat: 2, DimensionIntensity: 1},
+	"jalapeno":       {DimensionHeat: 2, DimensionIntensity: 1},
+	"cayenne":        {DimensionHeat: 3, DimensionIntensity: 2},
+	"curry paste":    {DimensionHeat: 2, DimensionSalt: 1, DimensionIntensity: 3},
+	"curry powder":   {DimensionHeat: 1, DimensionIntensity: 2},
+	"harissa":        {DimensionHeat: 2, DimensionIntensity: 2},
+	"gochujang":      {DimensionHeat: 2, DimensionSweetness: 1, DimensionUmami: 2, DimensionIntensity: 3},
+	"sriracha":       {DimensionHeat: 2, DimensionSweetness: 1, DimensionIntensity: 2},
+	"ginger":         {DimensionHeat: 1, DimensionIntensity: 2},
+	"black pepper":   {DimensionHeat: 1},
+	"paprika":        {DimensionIntensity: 1},
+	"smoked paprika": {DimensionBitterness: 1, DimensionIntensity: 2},
+	"cumin":          {DimensionBitterness: 1, DimensionIntensity: 1},
+	"bell pepper":    {DimensionSweetness: 1},
+	"red pepper":     {DimensionSweetness: 1},
+
+	// Seasonings and condiments
+	"salt":           {DimensionSalt: 3},
+	"soy sauce":      {DimensionSalt: 3, DimensionUmami: 3, DimensionIntensity: 2},
+	"fish sauce":     {DimensionSalt: 3, DimensionUmami: 3, DimensionIntensity: 2},
+	"miso":           {DimensionSalt: 3, DimensionUmami: 3, DimensionIntensity: 2},
+	"worcestershire": {DimensionSalt: 2, DimensionUmami: 2, DimensionAcidity: 1, DimensionIntensity: 2},
+	"stock":          {DimensionSalt: 1, DimensionUmami: 2},
+	"broth":          {DimensionSalt: 1, DimensionUmami: 2},
+	"vinegar":        {DimensionAcidity: 3, DimensionIntensity: 1},
+	"balsamic":       {DimensionAcidity: 2, DimensionSweetness: 2, DimensionIntensity: 2},
+	"mustard":        {DimensionAcidity: 1, DimensionHeat: 1, DimensionIntensity: 2},
+	"wine":           {DimensionAcidity: 2, DimensionIntensity: 1},
+	"red wine":       {DimensionAcidity: 2, DimensionBitterness: 1, DimensionIntensity: 2},
+	"beer":           {DimensionBitterness: 2, DimensionIntensity: 1},
+	"sugar":          {DimensionSweetness: 3},
+	"honey":          {DimensionSweetness: 3},
+	"maple syrup":    {DimensionSweetness: 3},
+	"chocolate":      {DimensionSweetness: 2, DimensionBitterness: 2, DimensionFat: 2, DimensionIntensity: 3},
+	"cocoa":          {DimensionBitterness: 3, DimensionIntensity: 2},
+	"coffee":         {DimensionBitterness: 3, DimensionIntensity: 2},
+	"tamarind":       {DimensionAcidity: 3, DimensionSweetness: 1, DimensionIntensity: 2},
+	"nut":            {DimensionFat: 2, DimensionIntensity: 1},
+	"tahini":         {DimensionFat: 2, DimensionBitterness: 1, DimensionIntensity: 1},
+	"herbs":          {DimensionIntensity: 1},
+	"rosemary":       {DimensionBitterness: 1, DimensionIntensity: 2},
+	"thyme":          {DimensionIntensity: 1},
+	"basil":          {DimensionIntensity: 1},
+	"coriander":      {DimensionIntensity: 1},
+	"truffle":        {DimensionUmami: 3, DimensionIntensity: 3},
+}
+
+// methodPatterns detect cooking methods from the title and instructions.
+// Simmering is left out as nearly every sauce is simmered.
+var methodPatterns = map[CookingMethod]*regexp.Regexp{
+	Poach:  regexp.MustCompile(`(?i)\bpoach`),
+	Steam:  regexp.MustCompile(`(?i)\bsteam`),
+	Braise: regexp.MustCompile(`(?i)\b(?:brais|stew|slow[- ]cook|casserole)`),
+	Roast:  regexp.MustCompile(`(?i)\broast`),
+	Grill:  regexp.MustCompile(`(?i)\b(?:grill|barbecue|bbq|char(?:s|red|ring|grill\w*)?\b|broil)`),
+	Fry:    regexp.MustCompile(`(?i)\b(?:fry|fried|sear|saut[ée])`),
+	Bake:   regexp.MustCompile(`(?i)\bbak(?:e|ed|ing)\b`),
+	Smoke:  regexp.MustCompile(`(?i)\bsmok(?:e|ed|ing)\b`),
+	Cure:   regexp.MustCompile(`(?i)\b(?:cure|cured|pickl)`),
+}
+
+// rawPattern detects raw dishes. It is only matched against the dish itself,
+// as instructions mention raw ingredients and salads served alongside.
+var rawPattern = regexp.MustCompile(`(?i)\b(?:raw|ceviche|tartare|carpaccio|crudo|sashimi|salad)\b`)
+
+// methodTastes gives the adjustment each cooking method makes to the profile
+var methodTastes = map[CookingMethod]taste{
+	Raw:    {DimensionAcidity: 0.5, DimensionIntensity: -1},
+	Poach:  {DimensionIntensity: -1},
+	Steam:  {DimensionIntensity: -1},
+	Braise: {DimensionUmami: 1, DimensionIntensity: 1},
+	Roast:  {DimensionUmami: 0.5, DimensionSweetness: 0.5, DimensionIntensity: 0.5},
+	Grill:  {DimensionBitterness: 1, DimensionIntensity: 1},
+	Fry:    {DimensionFat: 1, DimensionIntensity: 0.5},
+	Bake:   {},
+	Smoke:  {DimensionBitterness: 1, DimensionIntensity: 1.5},
+	Cure:   {DimensionSalt: 1, DimensionAcidity: 0.5},
+}
+
+// FlavorProfile scores a dish on the dimensions that matter for wine pairing.
+// Each score runs from 0 to 10.
+type FlavorProfile struct {
+	Scores  map[string]float64
+	Methods []CookingMethod
+
+	// Drivers lists the ingredients contributing most to each dimension, so
+	// pairing suggestions can be explained
+	Drivers map[string][]string
+}
+
+// AnalyzeFlavor computes the flavour profile of a recipe from its ingredients
+// and cooking methods
+func AnalyzeFlavor(r *Recipe) FlavorProfile {
+	profile := FlavorProfile{
+		Scores:  make(map[string]float64, len(dimensions)),
+		Drivers: make(map[string][]string),
+	}
+
+	ingredients := RankIngredients(r.ParsedIngredients())
+	if len(ingredients) == 0 {
+		return profile
+	}
+
+	// Weight each ingredient by its share of the dish on a log scale, so a
+	// tablespoon of soy sauce still registers next to a kilo of beef
+	maxWeight := math.Log1p(ingredients[0].Grams())
+	raw := make(map[string]float64, len(dimensions))
+	contributions := make(map[string]map[string]float64)
+	for _, ing := range ingredients {
+		t, ok := lookupTaste(ing.Item)
+		if !ok {
+			continue
+		}
+		prominence := 1.0
+		if maxWeight > 0 {
+			prominence = math.Log1p(ing.Grams()) / maxWeight
+		}
+		for dim, value := range t {
+			contribution := value * prominence
+			raw[dim] += contribution
+			if contributions[dim] == nil {
+				contributions[dim] = make(map[string]float64)
+			}
+			contributions[dim][ing.Item] += contribution
+		}
+	}
+
+	profile.Methods = detectMethods(r)
+	for _, method := range profile.Methods {
+		for dim, value := range methodTastes[method] {
+			raw[dim] += value
+		}
+	}
+
+	for _, dim := range dimensions {
+		// Saturate so that piling on ingredients approaches but never exceeds 10
+		score := 10 * (1 - math.Exp(-math.Max(raw[dim], 0)/3))
+		profile.Scores[dim] = math.Round(score*10) / 10
+		profile.Drivers[dim] = topDrivers(contributions[dim], 3)
+	}
+
+	return profile
+}
+
+// FlavorProfile computes the recipe's flavour profile
+func (r *Recipe) FlavorProfile() FlavorProfile {
+	return AnalyzeFlavor(r)
+}
+
+// lookupTaste finds the attribute table entry for an ingredient item. A key
+// naming the head noun wins, so "red wine vinegar" tastes of vinegar rather
+// than red wine and "chicken stock" of stock rather than chicken.
+func lookupTaste(item string) (taste, bool) {
+	words := strings.Fields(strings.ToLower(item))
+	best := ""
+	for name := range ingredientTastes {
+		n := len(strings.Fields(name))
+		if n > len(words) || len(name) < len(best) || (len(name) == len(best) && name > best) {
+			continue
+		}
+		if strings.HasPrefix(strings.Join(words[len(words)-n:], " "), name) {
+			best = name
+		}
+	}
+	if best != "" {
+		return ingredientTastes[best], true
+	}
+
+	name, ok := longestKeyword(item, ingredientTastes)
+	return ingredientTastes[name], ok
+}
+
+// detectMethods finds the cooking methods mentioned in the title, published
+// cooking method and instructions, in a stable order
+func detectMethods(r *Recipe) []CookingMethod {
+	dish := r.Title + "\n" + r.CookingMethod
+	text := dish + "\n" + strings.Join(r.Instructions, "\n")
+
+	var methods []CookingMethod
+	if rawPattern.MatchString(dish) {
+		methods = append(methods, Raw)
+	}
+	for method, pattern := range methodPatterns {
+		if pattern.MatchString(text) {
+			methods = append(methods, method)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i] < methods[j] })
+	return methods
+}
+
+// topDrivers returns the names of the n biggest contributors
+func topDrivers(contributions map[string]float64, n int) []string {
+	names := make([]string, 0, len(contributions))
+	for name, value := range contributions {
+		if value > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if contributions[names[i]] != contributions[names[j]] {
+			return contributions[names[i]] > contributions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	return names
+}
+
+// Level describes a 0-10 score as low, medium or high
+func Level(score float64) string {
+	switch {
+	case score >= 6.5:
+		return "high"
+	case score >= 3.5:
+		return "medium"
+	}
+	return "low"
+}
+
+// Format formats the profile for prompts and terminal output
+func (p FlavorProfile) Format() string {
+	if len(p.Scores) == 0 {
+		return "Unknown"
+	}
+
+	var lines []string
+	for _, dim := range dimensions {
+		score := p.Scores[dim]
+		line := fmt.Sprintf("%s: %s (%.1f/10)", dim, Level(score), score)
+		if drivers := p.Drivers[dim]; len(drivers) > 0 && score >= 3.5 {
+			line += fmt.Sprintf(" from %s", strings.Join(drivers, ", "))
+		}
+		lines = append(lines, line)
+	}
+
+	if len(p.Methods) > 0 {
+		methods := make([]string, len(p.Methods))
+		for i, m := range p.Methods {
+			methods[i] = string(m)
+		}
+		lines = append(lines, "cooking methods: "+strings.Join(methods, ", "))
+	}
+
+	return strings.Join(lines, "; ")
+}
+
+// FormatFlavorProfile formats the recipe's flavour profile for the prompt
+func (r *Recipe) FormatFlavorProfile() string {
+	return r.FlavorProfile().Format()
+}
@@ -0,0 +1,178 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeFlavor(t *testing.T) {
+	stew := &Recipe{
+		Title: "Beef and Ale Stew",
+		Ingredients: []string{
+			"1.5 kg beef shin, diced",
+			"2 tbsp olive oil",
+			"200g smoked bacon lardons",
+			"2 onions, chopped",
+			"500ml red wine",
+			"250g mushrooms",
+			"salt, to taste",
+		},
+		Instructions: []string{"Sear the beef in batches.", "Braise in the oven for 3 hours."},
+	}
+
+	ceviche := &Recipe{
+		Title: "Sea Bass Ceviche",
+		Ingredients: []string{
+			"300g white fish fillet",
+			"4 limes, juiced",
+			"1 red chilli, finely chopped",
+			"small bunch coriander",
+		},
+		Instructions: []string{"Marinate the raw fish in lime juice for 15 minutes."},
+	}
+
+	stewProfile := AnalyzeFlavor(stew)
+	cevicheProfile := AnalyzeFlavor(ceviche)
+
+	assert.Equal(t, []CookingMethod{Braise, Fry}, stewProfile.Methods)
+	assert.Equal(t, []CookingMethod{Raw}, cevicheProfile.Methods)
+
+	assert.Equal(t, "high", Level(stewProfile.Scores[DimensionIntensity]))
+	assert.Equal(t, "high", Level(stewProfile.Scores[DimensionUmami]))
+	assert.Greater(t, stewProfile.Scores[DimensionFat], cevicheProfile.Scores[DimensionFat])
+	assert.Greater(t, cevicheProfile.Scores[DimensionAcidity], stewProfile.Scores[DimensionAcidity])
+	assert.Greater(t, cevicheProfile.Scores[DimensionHeat], stewProfile.Scores[DimensionHeat])
+	assert.Less(t, cevicheProfile.Scores[DimensionIntensity], stewProfile.Scores[DimensionIntensity])
+
+	assert.Contains(t, stewProfile.Drivers[DimensionFat], "beef shin")
+	assert.Equal(t, "limes", cevicheProfile.Drivers[DimensionAcidity][0])
+
+	// Condiments named after a meat or a wine don't taste like them
+	vinaigrette := AnalyzeFlavor(&Recipe{
+		Title:       "Vinaigrette",
+		Ingredients: []string{"3 tbsp red wine vinegar", "500ml chicken stock"},
+	})
+	assert.Empty(t, vinaigrette.Drivers[DimensionBitterness])
+	assert.Empty(t, vinaigrette.Drivers[DimensionFat])
+	assert.Equal(t, []string{"red wine vinegar"}, vinaigrette.Drivers[DimensionAcidity])
+
+	for _, score := range stewProfile.Scores {
+		assert.GreaterOrEqual(t, score, 0.0)
+		assert.LessOrEqual(t, score, 10.0)
+	}
+}
+
+func TestAnalyzeFlavor_Methods(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe *Recipe
+		want   []CookingMethod
+	}{
+		{
+			name: "serving suggestion isn't raw",
+			recipe: &Recipe{
+				Title:        "Beef Stew",
+				Instructions: []string{"Stew the beef for 3 hours.", "Serve with a green salad."},
+			},
+			want: []CookingMethod{Braise},
+		},
+		{
+			name:   "chard isn't charred",
+			recipe: &Recipe{Title: "Swiss Chard Gratin", Instructions: []string{"Bake for 30 minutes."}},
+			want:   []CookingMethod{Bake},
+		},
+		{
+			name:   "charcuterie isn't charred",
+			recipe: &Recipe{Title: "Charcuterie Board"},
+		},
+		{
+			name:   "charred is grilled",
+			recipe: &Recipe{Title: "Charred Hispi Cabbage"},
+			want:   []CookingMethod{Grill},
+		},
+		{
+			name:   "simmering isn't braising",
+			recipe: &Recipe{Title: "Tomato Sauce", Instructions: []string{"Simmer for 20 minutes."}},
+		},
+		{
+			name:   "raw from the title",
+			recipe: &Recipe{Title: "Beef Carpaccio"},
+			want:   []CookingMethod{Raw},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectMethods(tt.recipe))
+		})
+	}
+}
+
+func TestLookupTaste(t *testing.T) {
+	tests := []struct {
+		item string
+		want string
+	}{
+		{item: "red wine vinegar", want: "vinegar"},
+		{item: "red wine", want: "red wine"},
+		{item: "chicken stock", want: "stock"},
+		{item: "beef broth", want: "broth"},
+		{item: "coconut milk", want: "coconut milk"},
+		{item: "beef shin", want: "beef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			got, ok := lookupTaste(tt.item)
+			assert.True(t, ok)
+			assert.Equal(t, ingredientTastes[tt.want], got)
+		})
+	}
+}
+
+func TestFlavorProfile_Format(t *testing.T) {
+	assert.Equal(t, "Unknown", AnalyzeFlavor(&Recipe{Title: "Mystery"}).Format())
+
+	profile := FlavorProfile{
+		Scores: map[string]float64{
+			DimensionFat:       7,
+			DimensionIntensity: 8,
+		},
+		Methods: []CookingMethod{Braise},
+		Drivers: map[string][]string{
+			DimensionFat:       {"beef shin", "butter"},
+			DimensionIntensity: {"beef shin"},
+		},
+	}
+	assert.Equal(t,
+		"fat: high (7.0/10) from beef shin, butter; acidity: low (0.0/10); salt: low (0.0/10); sweetness: low (0.0/10); "+
+			"heat: low (0.0/10); umami: low (0.0/10); bitterness: low (0.0/10); intensity: high (8.0/10) from beef shin; "+
+			"cooking methods: braise",
+		profile.Format(),
+	)
+}
+
+func TestLongestKeyword(t *testing.T) {
+	table := map[string]int{"ham": 1, "egg": 2, "eggplant": 3, "pea": 4, "peanut": 5}
+
+	tests := []struct {
+		text     string
+		expected string
+		found    bool
+	}{
+		{text: "Smoked Ham hock", expected: "ham", found: true},
+		{text: "champagne", expected: "", found: false},
+		{text: "2 large eggplants", expected: "eggplant", found: true},
+		{text: "free-range eggs", expected: "egg", found: true},
+		{text: "roasted peanuts", expected: "peanut", found: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := longestKeyword(tt.text, table)
+			assert.Equal(t, tt.found, ok)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package recipe
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	ingredientsContainer  = regexp.MustCompile(`(?i)ingredient`)
+	instructionsContainer = regexp.MustCompile(`(?i)instruction|method|direction|preparation|steps`)
+)
+
+// HeuristicExtractor is a last resort for pages without any structured markup.
+// It guesses at the recipe from common class names and page headings.
+type HeuristicExtractor struct{}
+
+// Name implements the RecipeExtractor interface
+func (e *HeuristicExtractor) Name() string {
+	return "heuristic"
+}
+
+// Extract implements the RecipeExtractor interface
+func (e *HeuristicExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	title := cleanText(doc.Find("meta[property='og:title']").AttrOr("content", ""))
+	if title == "" {
+		title = cleanText(doc.Find("h1").First().Text())
+	}
+
+	r := &Recipe{
+		Title:        title,
+		Ingredients:  listItemsIn(doc, ingredientsContainer),
+		Instructions: listItemsIn(doc, instructionsContainer),
+	}
+
+	// A title on its own is just any web page
+	if len(r.Ingredients) == 0 {
+		return nil, ErrNoRecipe
+	}
+
+	return single(newExtraction(e.Name(), r, 0.5))
+}
+
+// listItemsIn returns the list items inside the first element whose class or
+// id matches pattern
+func listItemsIn(doc *goquery.Document, pattern *regexp.Regexp) []string {
+	var items []string
+	doc.Find("[class], [id]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if !pattern.MatchString(s.AttrOr("class", "")) && !pattern.MatchString(s.AttrOr("id", "")) {
+			return true
+		}
+		items = texts(s.Find("li"))
+		return len(items) == 0
+	})
+	return items
+}
@@ -0,0 +1,288 @@
+package recipe
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Limits on what an export may expand to, so that a zip bomb, whether one
+// huge entry, many entries or archives nested inside each other, fails
+// rather than exhausting memory
+const (
+	maxImportEntrySize = 10 << 20  // 10 MiB, the fetcher's default page limit
+	maxImportSize      = 100 << 20 // 100 MiB across the whole export
+	maxImportEntries   = 10000
+	maxImportDepth     = 3 // Tandoor nests a zip per recipe inside its export
+)
+
+// ErrImportTooLarge is returned when an export decompresses to more than the
+// import limits allow, as a zip bomb would
+var ErrImportTooLarge = errors.New("import too large")
+
+// ImportFile reads every recipe from a recipe manager export: a Paprika
+// archive, a Mealie or Tandoor JSON export (or zip of them), or a Cooklang file
+func ImportFile(path string) ([]*Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	name := filepath.Base(path)
+	im := &importer{bytesLeft: maxImportSize, entriesLeft: maxImportEntries}
+	recipes, err := im.importData(name, data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import %s: %w", name, err)
+	}
+	if len(recipes) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoRecipe, name)
+	}
+	for _, r := range recipes {
+		setLanguage(r, "")
+	}
+	return recipes, nil
+}
+
+// importer tracks how much of the import limits one export has used
+type importer struct {
+	bytesLeft   int64
+	entriesLeft int
+}
+
+// importData dispatches on the file name, recursing into zip archives. depth
+// counts the archives data is nested inside.
+func (im *importer) importData(name string, data []byte, depth int) ([]*Recipe, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".paprikarecipes":
+		return im.importZip(data, depth, func(entry string) bool {
+			return strings.HasSuffix(strings.ToLower(entry), ".paprikarecipe")
+		})
+	case ".paprikarecipe":
+		r, err := parsePaprika(data, im)
+		if err != nil {
+			return nil, err
+		}
+		return []*Recipe{r}, nil
+	case ".cook":
+		return []*Recipe{parseCooklang(strings.TrimSuffix(name, filepath.Ext(name)), string(data))}, nil
+	case ".zip":
+		return im.importZip(data, depth, func(entry string) bool {
+			ext := strings.ToLower(filepath.Ext(entry))
+			return ext == ".json" || ext == ".zip" || ext == ".cook" || ext == ".paprikarecipe"
+		})
+	case ".json":
+		return parseExportJSON(data)
+	}
+	return nil, fmt.Errorf("unsupported import format")
+}
+
+// importZip imports every matching entry of a zip archive
+func (im *importer) importZip(data []byte, depth int, match func(string) bool) ([]*Recipe, error) {
+	if depth >= maxImportDepth {
+		return nil, fmt.Errorf("%w: archives nested more than %d deep", ErrImportTooLarge, maxImportDepth)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	var recipes []*Recipe
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || !match(f.Name) {
+			continue
+		}
+		if im.entriesLeft--; im.entriesLeft < 0 {
+			return nil, fmt.Errorf("%w: more than %d entries", ErrImportTooLarge, maxImportEntries)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		entry, err := im.read(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+
+		found, err := im.importData(f.Name, entry, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		recipes = append(recipes, found...)
+	}
+	return recipes, nil
+}
+
+// read reads all of a decompressing reader, failing once it passes
+// maxImportEntrySize or what is left of maxImportSize rather than reading on
+// without bound
+func (im *importer) read(r io.Reader) ([]byte, error) {
+	limit := min(int64(maxImportEntrySize), im.bytesLeft)
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		if limit < maxImportEntrySize {
+			return nil, fmt.Errorf("%w: export exceeds limit of %d bytes", ErrImportTooLarge, maxImportSize)
+		}
+		return nil, fmt.Errorf("%w: entry exceeds limit of %d bytes", ErrImportTooLarge, maxImportEntrySize)
+	}
+	im.bytesLeft -= int64(len(data))
+	return data, nil
+}
+
+// parseExportJSON reads a JSON export from Mealie or Tandoor, or plain
+// schema.org JSON-LD. Exports may hold one recipe, an array of them, or a
+// paginated {"items": [...]} response.
+func parseExportJSON(data []byte) ([]*Recipe, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	var items []json.RawMessage
+	switch v := raw.(type) {
+	case []interface{}:
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+	case map[string]interface{}:
+		if _, ok := v["items"]; ok {
+			var page struct {
+				Items []json.RawMessage `json:"items"`
+			}
+			if err := json.Unmarshal(data, &page); err != nil {
+				return nil, fmt.Errorf("failed to decode JSON: %w", err)
+			}
+			items = page.Items
+		} else {
+			items = []json.RawMessage{data}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected JSON document")
+	}
+
+	var recipes []*Recipe
+	for _, item := range items {
+		r, err := parseExportItem(item)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			recipes = append(recipes, r)
+		}
+	}
+	return recipes, nil
+}
+
+// parseExportItem works out which application exported a single JSON recipe
+func parseExportItem(data []byte) (*Recipe, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode recipe: %w", err)
+	}
+
+	switch {
+	case keys["@type"] != nil:
+		if recipes := parseJSONLD(string(data)); len(recipes) > 0 {
+			return recipes[0], nil
+		}
+		return nil, nil
+	case keys["steps"] != nil:
+		return parseTandoor(data)
+	case isMealie(keys):
+		if r, err := parseMealie(data); err == nil {
+			return r, nil
+		}
+	}
+
+	// Anything else may be a schema.org recipe that leaves out its @type
+	if keys["recipeIngredient"] != nil || keys["ingredients"] != nil {
+		var node map[string]interface{}
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("failed to decode recipe: %w", err)
+		}
+		if r := recipeFromJSONLD(node); r.Title != "" {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("unrecognised recipe export format")
+}
+
+// isMealie reports whether a JSON recipe has keys only Mealie uses: its slug
+// or snake_case ingredients, or ingredients as objects rather than the
+// strings schema.org uses
+func isMealie(keys map[string]json.RawMessage) bool {
+	if keys["slug"] != nil || keys["recipe_ingredient"] != nil {
+		return true
+	}
+	var ingredients []json.RawMessage
+	if json.Unmarshal(keys["recipeIngredient"], &ingredients) != nil {
+		return false
+	}
+	for _, ing := range ingredients {
+		if trimmed := bytes.TrimSpace(ing); len(trimmed) > 0 && trimmed[0] == '{' {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectRecipe picks a recipe by title, preferring an exact (case-insensitive)
+// match and falling back to a unique partial match. An empty title selects the
+// only recipe if there is just one.
+func SelectRecipe(recipes []*Recipe, title string) (*Recipe, error) {
+	if title == "" {
+		if len(recipes) == 1 {
+			return recipes[0], nil
+		}
+		return nil, fmt.Errorf("found %d recipes, choose one of: %s", len(recipes), titles(recipes))
+	}
+
+	var partial []*Recipe
+	for _, r := range recipes {
+		if strings.EqualFold(r.Title, title) {
+			return r, nil
+		}
+		if strings.Contains(strings.ToLower(r.Title), strings.ToLower(title)) {
+			partial = append(partial, r)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return nil, fmt.Errorf("no recipe titled %q, choose one of: %s", title, titles(recipes))
+	case 1:
+		return partial[0], nil
+	}
+	return nil, fmt.Errorf("%q matches %d recipes: %s", title, len(partial), titles(partial))
+}
+
+// titles lists recipe titles for error messages
+func titles(recipes []*Recipe) string {
+	names := make([]string, len(recipes))
+	for i, r := range recipes {
+		names[i] = fmt.Sprintf("%q", r.Title)
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitLines splits a block of text into trimmed, non-empty lines
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = unescapeText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,250 @@
+package recipe
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes a fixture into the test's temp dir and returns its path
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+// zipFiles builds a zip archive in memory
+func zipFiles(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestImportFile_Paprika(t *testing.T) {
+	archive := zipFiles(t, map[string][]byte{
+		"Beef Stew.paprikarecipe": gzipData(t, `{
+			"name": "Beef Stew",
+			"description": "A slow braise for winter",
+			"ingredients": "1 kg beef shin\n2 carrots\n",
+			"directions": "Brown the beef.\n\nSimmer for 3 hours.",
+			"servings": "4-6",
+			"prep_time": "20 mins",
+			"cook_time": "3 hours",
+			"categories": ["Main course"],
+			"source_url": "https://example.com/beef-stew",
+			"image_url": "https://example.com/beef-stew.jpg"
+		}`),
+		"Lemon Tart.paprikarecipe": gzipData(t, `{"name": "Lemon Tart", "ingredients": "4 lemons"}`),
+	})
+
+	recipes, err := ImportFile(writeFile(t, "export.paprikarecipes", archive))
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+
+	stew, err := SelectRecipe(recipes, "beef stew")
+	require.NoError(t, err)
+	assert.Equal(t, &Recipe{
+		Title:        "Beef Stew",
+		Description:  "A slow braise for winter",
+		Ingredients:  []string{"1 kg beef shin", "2 carrots"},
+		Instructions: []string{"Brown the beef.", "Simmer for 3 hours."},
+		PrepTime:     20 * time.Minute,
+		CookTime:     3 * time.Hour,
+		Yield:        "4-6",
+		Servings:     4,
+		Category:     []string{"Main course"},
+		Images:       []string{"https://example.com/beef-stew.jpg"},
+		Provenance:   Provenance{SourceURL: "https://example.com/beef-stew"},
+	}, stew)
+}
+
+func TestImportFile_Mealie(t *testing.T) {
+	path := writeFile(t, "mealie.json", []byte(`{"items": [{
+		"name": "Chicken Tikka",
+		"slug": "chicken-tikka",
+		"recipeYield": "4 servings",
+		"totalTime": "1 hour",
+		"recipeIngredient": [
+			{"display": "500 g chicken thighs, diced"},
+			{"quantity": 2, "unit": {"name": "tbsp"}, "food": {"name": "yoghurt"}, "note": ""}
+		],
+		"recipeInstructions": [{"text": "Marinate the chicken."}, {"text": "Grill."}],
+		"recipeCategory": [{"name": "Dinner"}],
+		"tags": [{"name": "Spicy"}, {"name": "Grill"}]
+	}]}`))
+
+	recipes, err := ImportFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []*Recipe{{
+		Title:        "Chicken Tikka",
+		Ingredients:  []string{"500 g chicken thighs, diced", "2 tbsp yoghurt"},
+		Instructions: []string{"Marinate the chicken.", "Grill."},
+		TotalTime:    time.Hour,
+		Yield:        "4 servings",
+		Servings:     4,
+		Category:     []string{"Dinner"},
+		Keywords:     []string{"Spicy", "Grill"},
+	}}, recipes)
+}
+
+func TestImportFile_SchemaOrgWithoutType(t *testing.T) {
+	path := writeFile(t, "recipes.json", []byte(`[
+		{"name": "Beef Stew", "recipeIngredient": ["1 kg beef shin", "2 carrots"], "recipeYield": "4"},
+		{"name": "Lemon Tart", "slug": "lemon-tart", "recipeIngredient": ["4 lemons"]}
+	]`))
+
+	recipes, err := ImportFile(path)
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+	assert.Equal(t, "Beef Stew", recipes[0].Title)
+	assert.Equal(t, []string{"1 kg beef shin", "2 carrots"}, recipes[0].Ingredients)
+	assert.Equal(t, 4, recipes[0].Servings)
+	assert.Equal(t, "Lemon Tart", recipes[1].Title)
+	assert.Equal(t, []string{"4 lemons"}, recipes[1].Ingredients)
+}
+
+func TestImportFile_Tandoor(t *testing.T) {
+	recipeJSON := []byte(`{
+		"name": "Risotto",
+		"servings": 2,
+		"working_time": 10,
+		"waiting_time": 25,
+		"steps": [
+			{"instruction": "Toast the rice.", "ingredients": [
+				{"food": {"name": "arborio rice"}, "unit": {"name": "g"}, "amount": 200, "note": ""},
+				{"is_header": true, "note": "For the stock"}
+			]},
+			{"instruction": "Add stock a ladle at a time.", "ingredients": [
+				{"food": {"name": "chicken stock"}, "unit": {"name": "l"}, "amount": 1, "note": "hot"}
+			]}
+		]
+	}`)
+	archive := zipFiles(t, map[string][]byte{
+		"1.zip": zipFiles(t, map[string][]byte{"recipe.json": recipeJSON}),
+	})
+
+	recipes, err := ImportFile(writeFile(t, "tandoor.zip", archive))
+	require.NoError(t, err)
+	assert.Equal(t, []*Recipe{{
+		Title:        "Risotto",
+		Ingredients:  []string{"200 g arborio rice", "1 l chicken stock, hot"},
+		Instructions: []string{"Toast the rice.", "Add stock a ladle at a time."},
+		PrepTime:     10 * time.Minute,
+		CookTime:     25 * time.Minute,
+		Yield:        "2",
+		Servings:     2,
+	}}, recipes)
+}
+
+func TestImportFile_Cooklang(t *testing.T) {
+	path := writeFile(t, "Carbonara.cook", []byte(`>> servings: 2
+>> cuisine: Italian
+>> course: Main
+>> diet: High protein
+
+-- Classic Roman method, no cream
+Boil @spaghetti{200%g} in a #large pot{} for ~{10%minutes}.
+
+Fry @guanciale{100%g} until crisp, then toss with @eggs{2}, @pecorino romano{50%g} and @pepper.
+`))
+
+	recipes, err := ImportFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []*Recipe{{
+		Title:       "Carbonara",
+		Ingredients: []string{"200 g spaghetti", "100 g guanciale", "2 eggs", "50 g pecorino romano", "pepper"},
+		Instructions: []string{
+			"Boil spaghetti in a large pot for 10 minutes.",
+			"Fry guanciale until crisp, then toss with eggs, pecorino romano and pepper.",
+		},
+		Yield:           "2",
+		Servings:        2,
+		Cuisine:         "Italian",
+		Language:        "en",
+		Category:        []string{"Main"},
+		SuitableForDiet: []string{"High protein"},
+	}}, recipes)
+}
+
+func TestImportFile_TooLarge(t *testing.T) {
+	// Zeros compress to almost nothing, so every export is small on disk
+	huge := bytes.Repeat([]byte{'0'}, maxImportEntrySize+1)
+
+	// Valid entries at the per-entry limit that add up to more than the whole
+	// export may hold
+	full := append(bytes.Repeat([]byte{' '}, maxImportEntrySize-2), "[]"...)
+	many := make(map[string][]byte)
+	for i := 0; i <= maxImportSize/maxImportEntrySize; i++ {
+		many[fmt.Sprintf("%d.json", i)] = full
+	}
+
+	// A recipe inside more zips than maxImportDepth allows
+	nested := zipFiles(t, map[string][]byte{"recipe.json": []byte(`{"@type": "Recipe", "name": "Stew"}`)})
+	for i := 0; i < maxImportDepth; i++ {
+		nested = zipFiles(t, map[string][]byte{"nested.zip": nested})
+	}
+
+	tests := map[string][]byte{
+		"export.zip":            zipFiles(t, map[string][]byte{"recipe.json": huge}),
+		"Bomb.paprikarecipe":    gzipData(t, string(huge)),
+		"export.paprikarecipes": zipFiles(t, map[string][]byte{"Bomb.paprikarecipe": gzipData(t, string(huge))}),
+		"many.zip":              zipFiles(t, many),
+		"nested.zip":            nested,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ImportFile(writeFile(t, name, data))
+			assert.ErrorIs(t, err, ErrImportTooLarge)
+		})
+	}
+}
+
+func TestSelectRecipe(t *testing.T) {
+	recipes := []*Recipe{{Title: "Beef Stew"}, {Title: "Beef Wellington"}, {Title: "Lemon Tart"}}
+
+	got, err := SelectRecipe(recipes, "LEMON TART")
+	require.NoError(t, err)
+	assert.Equal(t, "Lemon Tart", got.Title)
+
+	got, err = SelectRecipe(recipes, "welling")
+	require.NoError(t, err)
+	assert.Equal(t, "Beef Wellington", got.Title)
+
+	_, err = SelectRecipe(recipes, "beef")
+	assert.ErrorContains(t, err, "matches 2 recipes")
+
+	_, err = SelectRecipe(recipes, "")
+	assert.ErrorContains(t, err, "found 3 recipes")
+
+	got, err = SelectRecipe(recipes[:1], "")
+	require.NoError(t, err)
+	assert.Equal(t, "Beef Stew", got.Title)
+}
@@ -0,0 +1,464 @@
+package recipe
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Unit is a normalised unit of measure
+type Unit string
+
+const (
+	NoUnit     Unit = ""
+	Gram       Unit = "g"
+	Kilogram   Unit = "kg"
+	Milligram  Unit = "mg"
+	Ounce      Unit = "oz"
+	Pound      Unit = "lb"
+	Millilitre Unit = "ml"
+	Centilitre Unit = "cl"
+	Decilitre  Unit = "dl"
+	Litre      Unit = "l"
+	Teaspoon   Unit = "tsp"
+	Tablespoon Unit = "tbsp"
+	Cup        Unit = "cup"
+	FluidOunce Unit = "fl oz"
+	Pint       Unit = "pint"
+	Quart      Unit = "quart"
+	Pinch      Unit = "pinch"
+	Dash       Unit = "dash"
+	Clove      Unit = "clove"
+	Can        Unit = "can"
+	Bunch      Unit = "bunch"
+	Handful    Unit = "handful"
+	Slice      Unit = "slice"
+	Sprig      Unit = "sprig"
+	Stick      Unit = "stick"
+	Piece      Unit = "piece"
+)
+
+// unitAliases maps the ways units are written in recipes to their normalised form
+var unitAliases = map[string]Unit{
+	"g": Gram, "gr": Gram, "gram": Gram, "grams": Gram, "gramme": Gram, "grammes": Gram,
+	"kg": Kilogram, "kilo": Kilogram, "kilos": Kilogram, "kilogram": Kilogram, "kilograms": Kilogram,
+	"mg": Milligram, "milligram": Milligram, "milligrams": Milligram,
+	"oz": Ounce, "ounce": Ounce, "ounces": Ounce,
+	"lb": Pound, "lbs": Pound, "pound": Pound, "pounds": Pound,
+	"ml": Millilitre, "millilitre": Millilitre, "millilitres": Millilitre, "milliliter": Millilitre, "milliliters": Millilitre,
+	"cl": Centilitre, "centilitre": Centilitre, "centilitres": Centilitre,
+	"dl": Decilitre, "decilitre": Decilitre, "decilitres": Decilitre,
+	"l": Litre, "litre": Litre, "litres": Litre, "liter": Litre, "liters": Litre,
+	"tsp": Teaspoon, "tsps": Teaspoon, "teaspoon": Teaspoon, "teaspoons": Teaspoon,
+	"tbsp": Tablespoon, "tbsps": Tablespoon, "tbs": Tablespoon, "tablespoon": Tablespoon, "tablespoons": Tablespoon,
+	"cup": Cup, "cups": Cup,
+	"fl oz": FluidOunce, "fl. oz": FluidOunce, "fluid ounce": FluidOunce, "fluid ounces": FluidOunce,
+	"pint": Pint, "pints": Pint, "pt": Pint,
+	"quart": Quart, "quarts": Quart, "qt": Quart,
+	"pinch": Pinch, "pinches": Pinch,
+	"dash": Dash, "dashes": Dash, "splash": Dash,
+	"clove": Clove, "cloves": Clove,
+	"can": Can, "cans": Can, "tin": Can, "tins": Can,
+	"bunch": Bunch, "bunches": Bunch,
+	"handful": Handful, "handfuls": Handful,
+	"slice": Slice, "slices": Slice,
+	"sprig": Sprig, "sprigs": Sprig,
+	"stick": Stick, "sticks": Stick,
+	"piece": Piece, "pieces": Piece,
+}
+
+// unitGrams gives the approximate weight in grams of one of each unit. Volumes
+// assume the density of water, which is close enough for ranking.
+var unitGrams = map[Unit]float64{
+	Gram:       1,
+	Kilogram:   1000,
+	Milligram:  0.001,
+	Ounce:      28.35,
+	Pound:      453.6,
+	Millilitre: 1,
+	Centilitre: 10,
+	Decilitre:  100,
+	Litre:      1000,
+	Teaspoon:   5,
+	Tablespoon: 15,
+	Cup:        240,
+	FluidOunce: 29.57,
+	Pint:       568,
+	Quart:      946,
+	Pinch:      0.5,
+	Dash:       1,
+	Clove:      5,
+	Can:        400,
+	Bunch:      30,
+	Handful:    30,
+	Slice:      30,
+	Sprig:      1,
+	Stick:      113,
+	Piece:      100,
+}
+
+// massUnits and volumeUnits are the units Metric can convert to grams and
+// millilitres respectively. The rest are counts whose weight is only estimated.
+var (
+	massUnits = map[Unit]bool{
+		Gram: true, Kilogram: true, Milligram: true, Ounce: true, Pound: true,
+	}
+	volumeUnits = map[Unit]bool{
+		Millilitre: true, Centilitre: true, Decilitre: true, Litre: true,
+		Teaspoon: true, Tablespoon: true, Cup: true, FluidOunce: true, Pint: true, Quart: true,
+	}
+)
+
+// sortedUnitAliases lists the unit aliases longest first, so that "tbsp" isn't
+// read as "t" and "fl oz" isn't read as "fl"
+var sortedUnitAliases = func() []string {
+	aliases := make([]string, 0, len(unitAliases))
+	for alias := range unitAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		if len(aliases[i]) != len(aliases[j]) {
+			return len(aliases[i]) > len(aliases[j])
+		}
+		return aliases[i] < aliases[j]
+	})
+	return aliases
+}()
+
+// itemGrams estimates the weight of one whole item for ingredients counted
+// rather than measured, e.g. "2 onions"
+var itemGrams = map[string]float64{
+	"egg": 50, "onion": 150, "shallot": 30, "garlic": 5, "carrot": 80, "potato": 170,
+	"tomato": 120, "lemon": 100, "lime": 60, "orange": 150, "apple": 180, "pepper": 150,
+	"courgette": 200, "zucchini": 200, "aubergine": 300, "eggplant": 300, "leek": 200,
+	"chilli": 10, "chili": 10, "bay leaf": 0.2, "chicken": 1500, "chicken breast": 170,
+	"chicken thigh": 120, "duck breast": 200, "steak": 250, "fillet": 150, "chop": 200,
+	"sausage": 70, "avocado": 170, "banana": 120, "cucumber": 300, "celery": 40,
+	"stock cube": 10, "stock pot": 28, "bouillon": 10,
+}
+
+// wholeItems are itemGrams entries that only weigh the whole item when they
+// are the head noun, so "1 chicken" is a bird but "1 chicken liver" isn't
+var wholeItems = map[string]bool{"chicken": true}
+
+var (
+	unicodeFractions = map[rune]float64{
+		'½': 0.5, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 0.25, '¾': 0.75,
+		'⅕': 0.2, '⅛': 0.125, '⅜': 0.375, '⅝': 0.625, '⅞': 0.875,
+	}
+	numberWords = map[string]float64{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+		"six": 6, "half": 0.5, "a half": 0.5, "dozen": 12, "a dozen": 12,
+	}
+
+	parenthetical = regexp.MustCompile(`\(([^)]*)\)`)
+	optionalNote  = regexp.MustCompile(`(?i),?\s*\boptional\b`)
+	garnishNote   = regexp.MustCompile(`(?i)\b(?:to taste|to serve|for serving|for garnish|to garnish|for dusting|for greasing)\b`)
+	rangeJoin     = regexp.MustCompile(`^\s*(?:-|–|—|to|or)\s*`)
+	mixedFraction = regexp.MustCompile(`^(\d+)\s+(\d+)/(\d+)`)
+	fraction      = regexp.MustCompile(`^(\d+)/(\d+)`)
+	decimal       = regexp.MustCompile(`^\d+(?:[.,]\d+)?`)
+)
+
+// Ingredient is a single ingredient line broken down into its parts
+type Ingredient struct {
+	Raw         string
+	Quantity    float64
+	QuantityMax float64 // Upper bound for ranges like "2-3", otherwise 0
+	Unit        Unit
+	Item        string
+	Preparation string
+	Optional    bool
+}
+
+// ParseIngredient breaks an ingredient line such as "1½ cups plain flour,
+// sifted" into quantity, unit, item and preparation
+func ParseIngredient(line string) Ingredient {
+	ing := Ingredient{Raw: cleanText(line)}
+	rest := ing.Raw
+
+	if optionalNote.MatchString(rest) {
+		ing.Optional = true
+		rest = optionalNote.ReplaceAllString(rest, "")
+	}
+
+	// Pull out parenthetical notes like "(about 200g)" before they confuse
+	// the quantity and unit parsing
+	var notes []string
+	for _, m := range parenthetical.FindAllStringSubmatch(rest, -1) {
+		if note := strings.TrimSpace(m[1]); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	rest = cleanText(parenthetical.ReplaceAllString(rest, ""))
+
+	ing.Quantity, rest = parseQuantity(rest)
+	if ing.Quantity > 0 {
+		if m := rangeJoin.FindString(rest); m != "" {
+			if upper, after := parseQuantity(rest[len(m):]); upper > ing.Quantity {
+				ing.QuantityMax, rest = upper, after
+			}
+		}
+	}
+
+	ing.Unit, rest = parseUnit(rest)
+	rest = strings.TrimSpace(rest)
+	rest = strings.TrimPrefix(rest, "of ")
+
+	if i := strings.Index(rest, ","); i != -1 {
+		notes = append([]string{strings.TrimSpace(rest[i+1:])}, notes...)
+		rest = rest[:i]
+	}
+
+	ing.Item = strings.TrimSpace(rest)
+	ing.Preparation = strings.Join(nonEmpty(notes), "; ")
+	return ing
+}
+
+// ParseIngredients parses every ingredient line, skipping blank lines
+func ParseIngredients(lines []string) []Ingredient {
+	var result []Ingredient
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		result = append(result, ParseIngredient(line))
+	}
+	return result
+}
+
+// parseQuantity reads a leading number (whole, decimal, fraction, mixed
+// fraction, unicode fraction or number word) and returns it with the rest of
+// the string. Returns 0 if the string doesn't start with a quantity.
+func parseQuantity(s string) (float64, string) {
+	s = strings.TrimSpace(s)
+
+	if m := mixedFraction.FindStringSubmatch(s); m != nil {
+		whole, _ := strconv.ParseFloat(m[1], 64)
+		return whole + ratio(m[2], m[3]), s[len(m[0]):]
+	}
+	if m := fraction.FindStringSubmatch(s); m != nil {
+		return ratio(m[1], m[2]), s[len(m[0]):]
+	}
+
+	var value float64
+	if m := decimal.FindString(s); m != "" {
+		value, _ = strconv.ParseFloat(strings.Replace(m, ",", ".", 1), 64)
+		s = s[len(m):]
+	}
+
+	// Unicode fractions, either alone ("½") or after a whole number ("1½")
+	trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+	for _, r := range trimmed {
+		if f, ok := unicodeFractions[r]; ok {
+			return value + f, trimmed[len(string(r)):]
+		}
+		break
+	}
+	if value > 0 {
+		return value, s
+	}
+
+	lower := strings.ToLower(s)
+	for _, phrase := range []string{"a dozen", "a half", "dozen", "half", "one", "two", "three", "four", "five", "six", "an", "a"} {
+		if strings.HasPrefix(lower, phrase+" ") {
+			return numberWords[phrase], s[len(phrase):]
+		}
+	}
+	return 0, s
+}
+
+// parseUnit reads a leading unit, which may be attached to the quantity as in
+// "400g", and returns it with the rest of the string
+func parseUnit(s string) (Unit, string) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	lower := strings.ToLower(s)
+
+	for _, alias := range sortedUnitAliases {
+		if !strings.HasPrefix(lower, alias) {
+			continue
+		}
+		rest := s[len(alias):]
+		rest = strings.TrimPrefix(rest, ".")
+		// The unit must be a whole word, so "gingers" isn't "g" + "ingers"
+		if rest != "" && (unicode.IsLetter(rune(rest[0])) || unicode.IsDigit(rune(rest[0]))) {
+			continue
+		}
+		return unitAliases[alias], rest
+	}
+	return NoUnit, s
+}
+
+func ratio(num, den string) float64 {
+	n, _ := strconv.ParseFloat(num, 64)
+	d, _ := strconv.ParseFloat(den, 64)
+	if d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Metric converts the quantity to grams or millilitres where the unit allows,
+// returning the original quantity and unit otherwise
+func (i Ingredient) Metric() (float64, Unit) {
+	switch {
+	case massUnits[i.Unit]:
+		return i.Quantity * unitGrams[i.Unit], Gram
+	case volumeUnits[i.Unit]:
+		return i.Quantity * unitGrams[i.Unit], Millilitre
+	}
+	return i.Quantity, i.Unit
+}
+
+// Grams estimates the weight of the ingredient, used to rank ingredients by
+// how much they contribute to the dish. Ingredients used only to taste or as
+// a garnish are capped so they never outrank the main components.
+func (i Ingredient) Grams() float64 {
+	quantity := i.Quantity
+	if i.QuantityMax > 0 {
+		quantity = (i.Quantity + i.QuantityMax) / 2
+	}
+
+	var grams float64
+	switch {
+	case quantity == 0:
+		grams = 1
+	case i.Unit == NoUnit:
+		grams = quantity * i.itemWeight()
+	default:
+		grams = quantity * unitGrams[i.Unit]
+	}
+
+	if i.Optional || garnishNote.MatchString(i.Raw) {
+		grams = min(grams, 0.1)
+	}
+	return grams
+}
+
+// itemWeight estimates the weight of a single counted item
+func (i Ingredient) itemWeight() float64 {
+	name, ok := longestKeyword(i.Item, itemGrams)
+	if !ok || (wholeItems[name] && headNoun(i.Item) != name) {
+		return 100
+	}
+	return itemGrams[name]
+}
+
+// headNoun returns the last word of an item in the singular, which in English
+// names what the item is: "stock" in "chicken stock"
+func headNoun(item string) string {
+	words := strings.Fields(strings.ToLower(item))
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(words[len(words)-1], "s")
+}
+
+// String formats the ingredient in a normalised form for prompts
+func (i Ingredient) String() string {
+	var parts []string
+	if i.Quantity > 0 {
+		q := strconv.FormatFloat(i.Quantity, 'f', -1, 64)
+		if i.QuantityMax > 0 {
+			q += "-" + strconv.FormatFloat(i.QuantityMax, 'f', -1, 64)
+		}
+		parts = append(parts, q)
+	}
+	if i.Unit != NoUnit {
+		parts = append(parts, string(i.Unit))
+	}
+	parts = append(parts, i.Item)
+
+	s := strings.Join(parts, " ")
+	if i.Optional {
+		s += " (optional)"
+	}
+	return s
+}
+
+// RankIngredients returns the ingredients ordered from most to least
+// prominent by estimated weight
+func RankIngredients(ingredients []Ingredient) []Ingredient {
+	ranked := make([]Ingredient, len(ingredients))
+	copy(ranked, ingredients)
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return ranked[a].Grams() > ranked[b].Grams()
+	})
+	return ranked
+}
+
+// ParsedIngredients parses the recipe's ingredient lines
+func (r *Recipe) ParsedIngredients() []Ingredient {
+	return ParseIngredients(r.Ingredients)
+}
+
+// KeyIngredients returns up to n of the recipe's most prominent ingredients
+func (r *Recipe) KeyIngredients(n int) []Ingredient {
+	ranked := RankIngredients(r.ParsedIngredients())
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// FormatKeyIngredients formats the dominant ingredients for the prompt
+func (r *Recipe) FormatKeyIngredients() string {
+	key := r.KeyIngredients(5)
+	if len(key) == 0 {
+		return "Unknown"
+	}
+	names := make([]string, len(key))
+	for i, ing := range key {
+		names[i] = ing.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// longestKeyword finds the longest key of table that appears in text at the
+// start of a word, so "beef" matches "beef shin" but "ham" doesn't match
+// "champagne". Ties are broken alphabetically to keep results stable.
+func longestKeyword[V any](text string, table map[string]V) (string, bool) {
+	text = strings.ToLower(text)
+	best := ""
+	for name := range table {
+		if len(name) < len(best) || (len(name) == len(best) && name > best) {
+			continue
+		}
+		if containsWordPrefix(text, name) {
+			best = name
+		}
+	}
+	return best, best != ""
+}
+
+// containsWordPrefix reports whether word appears in text at a word boundary
+func containsWordPrefix(text, word string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i == -1 {
+			return false
+		}
+		i += offset
+		if i == 0 {
+			return true
+		}
+		prev, _ := utf8.DecodeLastRuneInString(text[:i])
+		if !unicode.IsLetter(prev) {
+			return true
+		}
+		offset = i + 1
+	}
+}
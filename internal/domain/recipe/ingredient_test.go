@@ -0,0 +1,122 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIngredient(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected Ingredient
+	}{
+		{
+			line:     "2 kg beef shin, cut into chunks",
+			expected: Ingredient{Quantity: 2, Unit: Kilogram, Item: "beef shin", Preparation: "cut into chunks"},
+		},
+		{
+			line:     "a pinch of salt",
+			expected: Ingredient{Quantity: 1, Unit: Pinch, Item: "salt"},
+		},
+		{
+			line:     "1 1/2 cups plain flour, sifted",
+			expected: Ingredient{Quantity: 1.5, Unit: Cup, Item: "plain flour", Preparation: "sifted"},
+		},
+		{
+			line:     "1½ Tablespoons olive oil",
+			expected: Ingredient{Quantity: 1.5, Unit: Tablespoon, Item: "olive oil"},
+		},
+		{
+			line:     "¾ tsp. ground cumin",
+			expected: Ingredient{Quantity: 0.75, Unit: Teaspoon, Item: "ground cumin"},
+		},
+		{
+			line:     "2-3 cloves garlic, crushed",
+			expected: Ingredient{Quantity: 2, QuantityMax: 3, Unit: Clove, Item: "garlic", Preparation: "crushed"},
+		},
+		{
+			line:     "400g tin chopped tomatoes",
+			expected: Ingredient{Quantity: 400, Unit: Gram, Item: "tin chopped tomatoes"},
+		},
+		{
+			line:     "1 large onion (about 200g), finely chopped",
+			expected: Ingredient{Quantity: 1, Item: "large onion", Preparation: "finely chopped; about 200g"},
+		},
+		{
+			line:     "8 fl oz double cream",
+			expected: Ingredient{Quantity: 8, Unit: FluidOunce, Item: "double cream"},
+		},
+		{
+			line:     "fresh coriander, to serve (optional)",
+			expected: Ingredient{Item: "fresh coriander", Preparation: "to serve", Optional: true},
+		},
+		{
+			line:     "1,5 l chicken stock",
+			expected: Ingredient{Quantity: 1.5, Unit: Litre, Item: "chicken stock"},
+		},
+		{
+			line:     "2 to 4 green chillies",
+			expected: Ingredient{Quantity: 2, QuantityMax: 4, Item: "green chillies"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			tt.expected.Raw = tt.line
+			assert.Equal(t, tt.expected, ParseIngredient(tt.line))
+		})
+	}
+}
+
+func TestIngredient_Metric(t *testing.T) {
+	quantity, unit := ParseIngredient("1 lb pork belly").Metric()
+	assert.InDelta(t, 453.6, quantity, 0.01)
+	assert.Equal(t, Gram, unit)
+
+	quantity, unit = ParseIngredient("2 cups milk").Metric()
+	assert.InDelta(t, 480, quantity, 0.01)
+	assert.Equal(t, Millilitre, unit)
+
+	quantity, unit = ParseIngredient("3 cloves garlic").Metric()
+	assert.Equal(t, 3.0, quantity)
+	assert.Equal(t, Clove, unit)
+}
+
+func TestRecipe_KeyIngredients(t *testing.T) {
+	r := &Recipe{
+		Ingredients: []string{
+			"a pinch of salt",
+			"2 tbsp olive oil",
+			"1.5 kg beef shin",
+			"2 onions",
+			"parsley, to garnish",
+			"750ml red wine",
+		},
+	}
+
+	var items []string
+	for _, ing := range r.KeyIngredients(3) {
+		items = append(items, ing.Item)
+	}
+	assert.Equal(t, []string{"beef shin", "red wine", "onions"}, items)
+	assert.Equal(t, "1.5 kg beef shin, 750 ml red wine, 2 onions, 2 tbsp olive oil, 1 pinch salt", r.FormatKeyIngredients())
+
+	// Pantry items named after a meat aren't weighed as the meat itself
+	r = &Recipe{
+		Ingredients: []string{
+			"1 chicken stock cube",
+			"2 tbsp chicken stock powder",
+			"1 chicken liver",
+			"1 kg beef shin",
+			"1 whole chicken",
+		},
+	}
+	items = nil
+	for _, ing := range r.KeyIngredients(2) {
+		items = append(items, ing.Item)
+	}
+	assert.Equal(t, []string{"whole chicken", "beef shin"}, items)
+	assert.Equal(t, 10.0, ParseIngredient("1 chicken stock cube").Grams())
+	assert.Equal(t, 100.0, ParseIngredient("1 chicken liver").Grams())
+}
@@ -0,0 +1,274 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JSONLDExtractor reads schema.org Recipe nodes from JSON-LD script blocks
+type JSONLDExtractor struct{}
+
+// Name implements the RecipeExtractor interface
+func (e *JSONLDExtractor) Name() string {
+	return "json-ld"
+}
+
+// Extract implements the RecipeExtractor interface
+func (e *JSONLDExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	return newExtractions(e.Name(), extractJSONLD(doc), 1.0)
+}
+
+// extractJSONLD looks through every JSON-LD block on the page and maps each
+// schema.org Recipe node it finds onto a Recipe
+func extractJSONLD(doc *goquery.Document) []*Recipe {
+	var found []*Recipe
+
+	doc.Find("script[type='application/ld+json']").Each(func(i int, s *goquery.Selection) {
+		found = append(found, parseJSONLD(s.Text())...)
+	})
+
+	return found
+}
+
+// parseJSONLD decodes a JSON-LD document and maps its Recipe nodes, skipping
+// any that aren't usable
+func parseJSONLD(raw string) []*Recipe {
+	var data interface{}
+	if err := json.Unmarshal([]byte(sanitizeJSONLD(raw)), &data); err != nil {
+		return nil
+	}
+
+	var recipes []*Recipe
+	for _, node := range findRecipeNodes(data) {
+		if r := recipeFromJSONLD(node); r.Title != "" {
+			recipes = append(recipes, r)
+		}
+	}
+	return recipes
+}
+
+// sanitizeJSONLD strips the wrappers and raw control characters that publishers
+// commonly leave in their JSON-LD blocks and that encoding/json rejects
+func sanitizeJSONLD(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "<![CDATA[")
+	raw = strings.TrimSuffix(raw, "]]>")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, raw)
+}
+
+// findRecipeNodes walks a decoded JSON-LD document depth-first and returns
+// every object typed as a schema.org Recipe, in document order
+func findRecipeNodes(data interface{}) []map[string]interface{} {
+	var nodes []map[string]interface{}
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			nodes = append(nodes, findRecipeNodes(item)...)
+		}
+	case map[string]interface{}:
+		if isRecipeType(v["@type"]) {
+			return []map[string]interface{}{v}
+		}
+		// Check @graph first as that's where most sites put their nodes
+		nodes = append(nodes, findRecipeNodes(v["@graph"])...)
+		// Map order is random, so visit the other keys in a fixed order
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if key != "@graph" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			nodes = append(nodes, findRecipeNodes(v[key])...)
+		}
+	}
+	return nodes
+}
+
+// isRecipeType reports whether a JSON-LD @type value names a Recipe, either on
+// its own or as one of several types
+func isRecipeType(t interface{}) bool {
+	switch v := t.(type) {
+	case string:
+		v = strings.TrimPrefix(v, "http://schema.org/")
+		v = strings.TrimPrefix(v, "https://schema.org/")
+		v = strings.TrimPrefix(v, "schema:")
+		return v == "Recipe"
+	case []interface{}:
+		for _, item := range v {
+			if isRecipeType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recipeFromJSONLD maps a JSON-LD Recipe node onto our Recipe model
+func recipeFromJSONLD(node map[string]interface{}) *Recipe {
+	ingredients := jsonLDStrings(node["recipeIngredient"])
+	if len(ingredients) == 0 {
+		// "ingredients" is the deprecated name, but plenty of sites still use it
+		ingredients = jsonLDStrings(node["ingredients"])
+	}
+
+	nutrition, _ := node["nutrition"].(map[string]interface{})
+
+	yield := jsonLDYield(node["recipeYield"])
+	return &Recipe{
+		Title:           jsonLDText(node["name"]),
+		Description:     jsonLDText(node["description"]),
+		Ingredients:     ingredients,
+		Instructions:    jsonLDInstructions(node["recipeInstructions"]),
+		CookTime:        ParseDuration(jsonLDText(node["cookTime"])),
+		PrepTime:        ParseDuration(jsonLDText(node["prepTime"])),
+		TotalTime:       ParseDuration(jsonLDText(node["totalTime"])),
+		Yield:           yield,
+		Servings:        ParseServings(yield),
+		Cuisine:         strings.Join(jsonLDStrings(node["recipeCuisine"]), ", "),
+		Language:        normaliseLanguage(jsonLDText(node["inLanguage"])),
+		Category:        jsonLDStrings(node["recipeCategory"]),
+		Keywords:        splitKeywords(jsonLDStrings(node["keywords"])),
+		CookingMethod:   strings.Join(jsonLDStrings(node["cookingMethod"]), ", "),
+		SuitableForDiet: dietNames(jsonLDIRIs(node["suitableForDiet"])),
+		Nutrition: nutritionFrom(func(property string) string {
+			return jsonLDText(nutrition[property])
+		}),
+		Images: jsonLDImages(node["image"]),
+	}
+}
+
+// jsonLDText returns a single cleaned-up string from a scalar JSON-LD value
+func jsonLDText(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return unescapeText(t)
+	case float64:
+		return fmt.Sprintf("%g", t)
+	case []interface{}:
+		if len(t) > 0 {
+			return jsonLDText(t[0])
+		}
+	case map[string]interface{}:
+		if value, ok := t["@value"]; ok {
+			return jsonLDText(value)
+		}
+		return jsonLDText(t["name"])
+	}
+	return ""
+}
+
+// jsonLDStrings returns a list of non-empty strings from a value that may be a
+// single string or an array of them
+func jsonLDStrings(v interface{}) []string {
+	var result []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if s := jsonLDText(item); s != "" {
+				result = append(result, s)
+			}
+		}
+	default:
+		if s := jsonLDText(t); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// jsonLDIRIs returns the values of a property whose values are references,
+// written either as plain strings or as {"@id": ...} objects
+func jsonLDIRIs(v interface{}) []string {
+	var result []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			result = append(result, jsonLDIRIs(item)...)
+		}
+	case map[string]interface{}:
+		if id := jsonLDText(t["@id"]); id != "" {
+			result = append(result, id)
+		}
+	case string:
+		if t = strings.TrimSpace(t); t != "" {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// jsonLDImages returns image URLs from a value that may be a URL, an
+// ImageObject or an array of either
+func jsonLDImages(v interface{}) []string {
+	var result []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			result = append(result, jsonLDImages(item)...)
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"url", "contentUrl", "@id"} {
+			if u, ok := t[key].(string); ok && u != "" {
+				result = append(result, strings.TrimSpace(u))
+				break
+			}
+		}
+	case string:
+		if t = strings.TrimSpace(t); t != "" {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// jsonLDYield picks the most descriptive recipeYield value, since sites often
+// publish both "4" and "4 servings"
+func jsonLDYield(v interface{}) string {
+	var best string
+	for _, s := range jsonLDStrings(v) {
+		if len(s) > len(best) {
+			best = s
+		}
+	}
+	return best
+}
+
+// jsonLDInstructions flattens the many shapes recipeInstructions can take: a
+// single block of text, a list of strings, HowToSteps, or HowToSections of steps
+func jsonLDInstructions(v interface{}) []string {
+	var steps []string
+	switch t := v.(type) {
+	case string:
+		for _, line := range strings.Split(stripTags(t), "\n") {
+			if line = cleanText(line); line != "" {
+				steps = append(steps, line)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			steps = append(steps, jsonLDInstructions(item)...)
+		}
+	case map[string]interface{}:
+		if elements, ok := t["itemListElement"]; ok {
+			// HowToSection or ItemList
+			steps = append(steps, jsonLDInstructions(elements)...)
+		} else if text := jsonLDText(t["text"]); text != "" {
+			steps = append(steps, text)
+		} else if name := jsonLDText(t["name"]); name != "" {
+			steps = append(steps, name)
+		}
+	}
+	return steps
+}
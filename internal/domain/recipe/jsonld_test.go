@@ -0,0 +1,139 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDoc(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestExtractJSONLD(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected *Recipe
+	}{
+		{
+			name: "plain recipe object",
+			html: `<script type="application/ld+json">{
+				"@context": "https://schema.org",
+				"@type": "Recipe",
+				"name": "Beef Stew",
+				"recipeIngredient": ["1 kg beef shin", "2 carrots"],
+				"recipeInstructions": "Brown the beef.\nSimmer for 3 hours.",
+				"cookTime": "PT3H",
+				"prepTime": "PT20M",
+				"totalTime": "PT3H20M",
+				"recipeYield": ["4", "4 servings"],
+				"recipeCuisine": "British"
+			}</script>`,
+			expected: &Recipe{
+				Title:        "Beef Stew",
+				Ingredients:  []string{"1 kg beef shin", "2 carrots"},
+				Instructions: []string{"Brown the beef.", "Simmer for 3 hours."},
+				CookTime:     3 * time.Hour,
+				PrepTime:     20 * time.Minute,
+				TotalTime:    3*time.Hour + 20*time.Minute,
+				Yield:        "4 servings",
+				Servings:     4,
+				Cuisine:      "British",
+			},
+		},
+		{
+			name: "recipe inside @graph with multiple types",
+			html: `<script type="application/ld+json">{
+				"@context": "https://schema.org",
+				"@graph": [
+					{"@type": "WebPage", "name": "Not a recipe"},
+					{"@type": ["Recipe", "NewsArticle"], "name": "Coq au Vin &amp; Mash",
+					 "recipeIngredient": ["1 chicken"],
+					 "recipeInstructions": [
+						{"@type": "HowToStep", "text": "Joint the chicken."},
+						{"@type": "HowToStep", "name": "Braise in wine."}
+					 ],
+					 "recipeCuisine": ["French", "Burgundian"]}
+				]
+			}</script>`,
+			expected: &Recipe{
+				Title:        "Coq au Vin & Mash",
+				Ingredients:  []string{"1 chicken"},
+				Instructions: []string{"Joint the chicken.", "Braise in wine."},
+				Cuisine:      "French, Burgundian",
+			},
+		},
+		{
+			name: "top-level array with HowToSections",
+			html: `<script type="application/ld+json">[
+				{"@type": "Organization", "name": "Example"},
+				{"@type": "Recipe", "name": "Lasagne", "recipeYield": 6,
+				 "recipeInstructions": [
+					{"@type": "HowToSection", "name": "Ragu", "itemListElement": [
+						{"@type": "HowToStep", "text": "Brown the mince."}
+					]},
+					{"@type": "HowToSection", "name": "Assembly", "itemListElement": [
+						{"@type": "HowToStep", "text": "Layer and bake."}
+					]}
+				 ]}
+			]</script>`,
+			expected: &Recipe{
+				Title:        "Lasagne",
+				Instructions: []string{"Brown the mince.", "Layer and bake."},
+				Yield:        "6",
+				Servings:     6,
+			},
+		},
+		{
+			name:     "no recipe node",
+			html:     `<script type="application/ld+json">{"@type": "WebSite", "name": "Example"}</script>`,
+			expected: nil,
+		},
+		{
+			name:     "invalid JSON is skipped",
+			html:     `<script type="application/ld+json">{not json</script>`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := extractJSONLD(newDoc(t, tt.html))
+			if tt.expected == nil {
+				assert.Empty(t, actual)
+				return
+			}
+			require.Len(t, actual, 1)
+			assert.Equal(t, tt.expected, actual[0])
+		})
+	}
+}
+
+func TestExtractJSONLD_MultipleRecipes(t *testing.T) {
+	doc := newDoc(t, `
+		<script type="application/ld+json">{
+			"@context": "https://schema.org",
+			"@type": "ItemList",
+			"name": "10 Weeknight Pastas",
+			"itemListElement": [
+				{"@type": "ListItem", "position": 1, "item": {"@type": "Recipe", "name": "Cacio e Pepe", "recipeIngredient": ["200g spaghetti", "100g pecorino"]}},
+				{"@type": "ListItem", "position": 2, "item": {"@type": "Recipe", "name": "Puttanesca", "recipeIngredient": ["200g spaghetti", "50g olives"]}}
+			]
+		}</script>
+		<script type="application/ld+json">{"@type": "Recipe", "name": "Aglio e Olio"}</script>
+	`)
+
+	var titles []string
+	for _, r := range extractJSONLD(doc) {
+		titles = append(titles, r.Title)
+	}
+	assert.Equal(t, []string{"Cacio e Pepe", "Puttanesca", "Aglio e Olio"}, titles)
+}
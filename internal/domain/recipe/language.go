@@ -0,0 +1,153 @@
+package recipe
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// English is the language the pairing prompt works in
+const English = "en"
+
+// languageNames are the English names of the languages DetectLanguage knows
+var languageNames = map[string]string{
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"zh": "Chinese",
+}
+
+// languageWords are common function words and kitchen vocabulary for each
+// language written in the Latin alphabet. Words shared between languages,
+// such as "de", count towards each of them.
+var languageWords = map[string][]string{
+	"en": {"the", "and", "of", "with", "to", "into", "until", "add", "cup", "cups", "tablespoon", "tablespoons", "teaspoon", "teaspoons", "chopped", "salt", "pepper", "oil", "butter", "minutes", "heat", "stir", "or", "for"},
+	"fr": {"le", "la", "les", "et", "de", "du", "des", "au", "aux", "avec", "une", "un", "pour", "cuillère", "cuillères", "sel", "poivre", "huile", "beurre", "ajouter", "ajoutez", "faire", "cuire", "pendant", "dans", "sur"},
+	"it": {"il", "lo", "la", "gli", "le", "e", "di", "del", "della", "con", "per", "un", "una", "cucchiaio", "cucchiai", "sale", "pepe", "olio", "burro", "aggiungere", "cuocere", "minuti", "q.b.", "nel", "nella"},
+	"es": {"el", "la", "los", "las", "y", "de", "del", "con", "para", "un", "una", "cucharada", "cucharadas", "sal", "pimienta", "aceite", "mantequilla", "añadir", "cocinar", "minutos", "en", "hasta"},
+	"de": {"der", "die", "das", "und", "mit", "für", "ein", "eine", "el", "tl", "esslöffel", "teelöffel", "salz", "pfeffer", "öl", "butter", "minuten", "hinzufügen", "in", "bis", "den", "dem"},
+	"pt": {"o", "a", "os", "as", "e", "de", "do", "da", "com", "para", "um", "uma", "colher", "colheres", "sal", "pimenta", "azeite", "manteiga", "adicione", "minutos", "em", "até"},
+	"nl": {"de", "het", "en", "met", "voor", "een", "eetlepel", "eetlepels", "theelepel", "zout", "peper", "olie", "boter", "minuten", "toevoegen", "in", "tot"},
+}
+
+// minLanguageScore is how many distinct words must match before a Latin
+// script language is reported
+const minLanguageScore = 3
+
+// LanguageName returns the English name of a language code, or the code
+// itself if it isn't one DetectLanguage knows
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// normaliseLanguage reduces a declared language such as "fr-FR" or "pt_BR"
+// to its lower case primary subtag
+func normaliseLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i != -1 {
+		tag = tag[:i]
+	}
+	if len(tag) < 2 || len(tag) > 3 {
+		return ""
+	}
+	return tag
+}
+
+// pageLanguage returns the language a page declares for itself, from the
+// lang attribute of <html> or its og:locale, or an empty string
+func pageLanguage(doc *goquery.Document) string {
+	if lang := normaliseLanguage(doc.Find("html").AttrOr("lang", "")); lang != "" {
+		return lang
+	}
+	return normaliseLanguage(doc.Find("meta[property='og:locale']").AttrOr("content", ""))
+}
+
+// DetectLanguage guesses the language of a recipe from its ingredients and
+// method, returning a language code such as "fr", or an empty string if the
+// text is too short or too mixed to tell
+func DetectLanguage(r *Recipe) string {
+	text := strings.Join(append(append([]string{r.Title}, r.Ingredients...), r.Instructions...), "\n")
+
+	// Scripts other than Latin give the language away
+	var kana, hangul, han, cyrillic, letters int
+	for _, c := range text {
+		switch {
+		case unicode.In(c, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, c):
+			hangul++
+		case unicode.Is(unicode.Han, c):
+			han++
+		case unicode.Is(unicode.Cyrillic, c):
+			cyrillic++
+		}
+		if unicode.IsLetter(c) {
+			letters++
+		}
+	}
+	switch {
+	case letters == 0:
+		return ""
+	case kana > 0 && (kana+han)*4 > letters:
+		return "ja"
+	case hangul*4 > letters:
+		return "ko"
+	case han*4 > letters:
+		return "zh"
+	case cyrillic*4 > letters:
+		return "ru"
+	}
+
+	lower := strings.ToLower(text)
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(lower, func(c rune) bool { return !unicode.IsLetter(c) }) {
+		words[w] = true
+	}
+	// Italian recipes season "q.b." (quanto basta, to taste)
+	if strings.Contains(lower, "q.b.") {
+		words["q.b."] = true
+	}
+
+	best, bestScore, runnerUp := "", 0, 0
+	for lang, vocabulary := range languageWords {
+		score := 0
+		for _, w := range vocabulary {
+			if words[w] {
+				score++
+			}
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore, runnerUp = lang, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < minLanguageScore || bestScore == runnerUp {
+		return ""
+	}
+	return best
+}
+
+// setLanguage fills in the recipe's language if the markup didn't declare
+// one, preferring what the text looks like over what the page claims, since
+// site templates often declare a language the recipe isn't written in
+func setLanguage(r *Recipe, declared string) {
+	if r.Language != "" {
+		return
+	}
+	if r.Language = DetectLanguage(r); r.Language == "" {
+		r.Language = declared
+	}
+}
@@ -0,0 +1,84 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		recipe   *Recipe
+		expected string
+	}{
+		{
+			name: "English",
+			recipe: &Recipe{
+				Ingredients:  []string{"2 tablespoons olive oil", "1 onion, chopped"},
+				Instructions: []string{"Heat the oil and add the onion.", "Stir until soft."},
+			},
+			expected: "en",
+		},
+		{
+			name: "French",
+			recipe: &Recipe{
+				Title:        "Bœuf bourguignon",
+				Ingredients:  []string{"1 kg de bœuf", "2 cuillères à soupe d'huile", "sel et poivre"},
+				Instructions: []string{"Faire revenir le bœuf dans l'huile.", "Cuire pendant 3 heures."},
+			},
+			expected: "fr",
+		},
+		{
+			name: "Italian",
+			recipe: &Recipe{
+				Title:        "Spaghetti alla carbonara",
+				Ingredients:  []string{"320 g di spaghetti", "150 g di guanciale", "pepe nero q.b."},
+				Instructions: []string{"Cuocere la pasta in abbondante acqua salata.", "Unire il guanciale con le uova."},
+			},
+			expected: "it",
+		},
+		{
+			name: "Spanish",
+			recipe: &Recipe{
+				Title:        "Tortilla de patatas",
+				Ingredients:  []string{"4 patatas", "6 huevos", "aceite de oliva", "sal"},
+				Instructions: []string{"Freír las patatas en el aceite hasta que estén tiernas.", "Añadir los huevos batidos."},
+			},
+			expected: "es",
+		},
+		{
+			name: "Japanese",
+			recipe: &Recipe{
+				Title:       "肉じゃが",
+				Ingredients: []string{"じゃがいも 3個", "牛肉 200g", "しょうゆ 大さじ3"},
+			},
+			expected: "ja",
+		},
+		{
+			name:     "too short to tell",
+			recipe:   &Recipe{Title: "Beef Stew", Ingredients: []string{"1 kg beef"}},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectLanguage(tt.recipe))
+		})
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	declared := &Recipe{Language: "it", Ingredients: []string{"1 onion, chopped", "salt and pepper to taste"}}
+	setLanguage(declared, "en")
+	assert.Equal(t, "it", declared.Language, "markup declared the language")
+
+	detected := &Recipe{Ingredients: []string{"2 oignons", "sel et poivre", "1 cuillère de beurre"}}
+	setLanguage(detected, "en")
+	assert.Equal(t, "fr", detected.Language, "text outweighs the page's declared language")
+
+	unknown := &Recipe{Title: "Beef Stew"}
+	setLanguage(unknown, "en")
+	assert.Equal(t, "en", unknown.Language)
+}
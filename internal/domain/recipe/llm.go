@@ -0,0 +1,126 @@
+package recipe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
+)
+
+// maxReadableText caps how much page text is sent to the LLM
+const maxReadableText = 20000
+
+// ExtractionPromptGenerator builds the prompt used for LLM recipe extraction
+type ExtractionPromptGenerator interface {
+	GenerateRecipeExtractionPrompt(text string) (string, error)
+}
+
+// LLMExtractorName identifies recipes found by the LLM in their Provenance
+const LLMExtractorName = "llm"
+
+// LLMExtractor asks a language model to find the recipe in a page's text. It
+// is used as a last resort when no structured extractor succeeds.
+type LLMExtractor struct {
+	llm       client.LLMClient
+	promptGen ExtractionPromptGenerator
+}
+
+// llmRecipe mirrors config/recipe_schema.json
+type llmRecipe struct {
+	Title        string   `json:"title"`
+	Ingredients  []string `json:"ingredients"`
+	Instructions []string `json:"instructions"`
+	CookTime     string   `json:"cook_time"`
+	PrepTime     string   `json:"prep_time"`
+	TotalTime    string   `json:"total_time"`
+	Yield        string   `json:"yield"`
+	Cuisine      string   `json:"cuisine"`
+}
+
+// NewLLMExtractor creates a new LLM extractor. llm should validate responses
+// against the recipe extraction schema.
+func NewLLMExtractor(llm client.LLMClient, promptGen ExtractionPromptGenerator) *LLMExtractor {
+	return &LLMExtractor{
+		llm:       llm,
+		promptGen: promptGen,
+	}
+}
+
+// Extract sends the readable text of the page to the LLM and parses the result
+func (e *LLMExtractor) Extract(ctx context.Context, doc *goquery.Document) (*Recipe, error) {
+	text := readableText(doc)
+	if text == "" {
+		return nil, ErrNoRecipe
+	}
+	return e.ExtractText(ctx, text)
+}
+
+// ExtractText asks the LLM to find a recipe in plain text
+func (e *LLMExtractor) ExtractText(ctx context.Context, text string) (*Recipe, error) {
+	prompt, err := e.promptGen.GenerateRecipeExtractionPrompt(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate prompt: %w", err)
+	}
+
+	response, err := e.llm.Complete(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract recipe: %w", err)
+	}
+
+	var extracted llmRecipe
+	if err := json.Unmarshal([]byte(response), &extracted); err != nil {
+		return nil, fmt.Errorf("failed to decode extracted recipe: %w", err)
+	}
+
+	if extracted.Title == "" || len(extracted.Ingredients) == 0 {
+		return nil, ErrNoRecipe
+	}
+
+	return &Recipe{
+		Title:        extracted.Title,
+		Ingredients:  extracted.Ingredients,
+		Instructions: extracted.Instructions,
+		CookTime:     ParseDuration(extracted.CookTime),
+		PrepTime:     ParseDuration(extracted.PrepTime),
+		TotalTime:    ParseDuration(extracted.TotalTime),
+		Yield:        extracted.Yield,
+		Servings:     ParseServings(extracted.Yield),
+		Cuisine:      extracted.Cuisine,
+		LLMExtracted: true,
+		Provenance:   Provenance{Extractor: LLMExtractorName},
+	}, nil
+}
+
+// readableText strips page chrome (navigation, scripts, comments and so on)
+// and returns the main article text with one block per line
+func readableText(doc *goquery.Document) string {
+	doc.Find("script, style, noscript, iframe, svg, nav, header, footer, aside, form, [role='navigation'], [aria-hidden='true'], .comments, #comments").Remove()
+
+	root := doc.Find("article").First()
+	if root.Length() == 0 {
+		root = doc.Find("main, [role='main']").First()
+	}
+	if root.Length() == 0 {
+		root = doc.Find("body")
+	}
+
+	root.Find("br, p, li, div, h1, h2, h3, h4, h5, h6, tr").Each(func(i int, s *goquery.Selection) {
+		s.AppendHtml("\n")
+	})
+
+	var lines []string
+	for _, line := range strings.Split(root.Text(), "\n") {
+		if line = cleanText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	text := strings.Join(lines, "\n")
+	if len(text) > maxReadableText {
+		text = strings.ToValidUTF8(text[:maxReadableText], "")
+	}
+	return text
+}
@@ -0,0 +1,99 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLLMClient returns a canned response and records the prompt it was sent
+type mockLLMClient struct {
+	response string
+	err      error
+	prompt   string
+	calls    int
+}
+
+func (m *mockLLMClient) Complete(ctx context.Context, prompt string, opts ...client.Option) (string, error) {
+	m.prompt = prompt
+	m.calls++
+	return m.response, m.err
+}
+
+// mockPromptGenerator passes the page text straight through as the prompt
+type mockPromptGenerator struct{}
+
+func (m *mockPromptGenerator) GenerateRecipeExtractionPrompt(text string) (string, error) {
+	return text, nil
+}
+
+func TestReadableText(t *testing.T) {
+	doc := newDoc(t, `<html><body>
+		<nav><a href="/">Home</a></nav>
+		<script>var tracking = true;</script>
+		<article>
+			<h1>Nonna's   Ragù</h1>
+			<p>We first made this in Bologna.</p>
+			<ul><li>500g beef mince</li><li>1 onion</li></ul>
+			<aside>Subscribe to our newsletter!</aside>
+		</article>
+		<footer>Copyright</footer>
+	</body></html>`)
+
+	assert.Equal(t, "Nonna's Ragù\nWe first made this in Bologna.\n500g beef mince\n1 onion", readableText(doc))
+}
+
+func TestLLMExtractor_Extract(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		err      error
+		expected *Recipe
+		wantErr  bool
+	}{
+		{
+			name:     "recipe found",
+			response: `{"title": "Ragù", "ingredients": ["500g beef mince"], "instructions": ["Simmer."], "cook_time": "PT2H", "cuisine": "Italian"}`,
+			expected: &Recipe{
+				Title:        "Ragù",
+				Ingredients:  []string{"500g beef mince"},
+				Instructions: []string{"Simmer."},
+				CookTime:     2 * time.Hour,
+				Cuisine:      "Italian",
+				LLMExtracted: true,
+				Provenance:   Provenance{Extractor: LLMExtractorName},
+			},
+		},
+		{
+			name:     "no recipe in text",
+			response: `{"title": "", "ingredients": [], "instructions": []}`,
+			wantErr:  true,
+		},
+		{
+			name:    "LLM error",
+			err:     errors.New("validation error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mockLLMClient{response: tt.response, err: tt.err}
+			extractor := NewLLMExtractor(llm, &mockPromptGenerator{})
+
+			got, err := extractor.Extract(context.Background(), newDoc(t, "<article><p>Ragù</p></article>"))
+			assert.Equal(t, "Ragù", llm.prompt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
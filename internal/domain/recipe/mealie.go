@@ -0,0 +1,204 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mealieRecipe is a recipe from a Mealie export or API response. Mealie
+// follows schema.org naming but with structured ingredients.
+type mealieRecipe struct {
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	RecipeIngredient  []mealieIngredient `json:"recipeIngredient"`
+	RecipeIngredient2 []mealieIngredient `json:"recipe_ingredient"`
+	Instructions      []struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	} `json:"recipeInstructions"`
+	RecipeYield string `json:"recipeYield"`
+	PrepTime    string `json:"prepTime"`
+	CookTime    string `json:"cookTime"`
+	PerformTime string `json:"performTime"`
+	TotalTime   string `json:"totalTime"`
+	OrgURL      string `json:"orgURL"`
+	Categories  []struct {
+		Name string `json:"name"`
+	} `json:"recipeCategory"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+type mealieIngredient struct {
+	Display      string  `json:"display"`
+	OriginalText string  `json:"originalText"`
+	Note         string  `json:"note"`
+	Quantity     float64 `json:"quantity"`
+	Unit         *struct {
+		Name string `json:"name"`
+	} `json:"unit"`
+	Food *struct {
+		Name string `json:"name"`
+	} `json:"food"`
+}
+
+// line rebuilds the ingredient line, preferring Mealie's own rendering
+func (i mealieIngredient) line() string {
+	for _, s := range []string{i.Display, i.OriginalText} {
+		if s = unescapeText(s); s != "" {
+			return s
+		}
+	}
+
+	var parts []string
+	if i.Quantity > 0 {
+		parts = append(parts, strconv.FormatFloat(i.Quantity, 'f', -1, 64))
+	}
+	if i.Unit != nil && i.Unit.Name != "" {
+		parts = append(parts, i.Unit.Name)
+	}
+	if i.Food != nil && i.Food.Name != "" {
+		parts = append(parts, i.Food.Name)
+	}
+	line := strings.Join(parts, " ")
+	if i.Note != "" {
+		if line == "" {
+			return unescapeText(i.Note)
+		}
+		line += ", " + i.Note
+	}
+	return unescapeText(line)
+}
+
+// parseMealie decodes a Mealie recipe
+func parseMealie(data []byte) (*Recipe, error) {
+	var m mealieRecipe
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode Mealie recipe: %w", err)
+	}
+
+	r := &Recipe{
+		Title:       unescapeText(m.Name),
+		Description: unescapeText(m.Description),
+		PrepTime:    ParseDuration(m.PrepTime),
+		CookTime:    ParseDuration(m.CookTime),
+		TotalTime:   ParseDuration(m.TotalTime),
+		Yield:       unescapeText(m.RecipeYield),
+		Servings:    ParseServings(m.RecipeYield),
+		Provenance:  Provenance{SourceURL: strings.TrimSpace(m.OrgURL)},
+	}
+	if r.CookTime == 0 {
+		r.CookTime = ParseDuration(m.PerformTime)
+	}
+	for _, c := range m.Categories {
+		if name := unescapeText(c.Name); name != "" {
+			r.Category = append(r.Category, name)
+		}
+	}
+	for _, tag := range m.Tags {
+		if name := unescapeText(tag.Name); name != "" {
+			r.Keywords = append(r.Keywords, name)
+		}
+	}
+
+	for _, ing := range append(m.RecipeIngredient, m.RecipeIngredient2...) {
+		if line := ing.line(); line != "" {
+			r.Ingredients = append(r.Ingredients, line)
+		}
+	}
+	for _, step := range m.Instructions {
+		r.Instructions = append(r.Instructions, splitLines(step.Text)...)
+	}
+
+	return r, nil
+}
+
+// tandoorRecipe is the recipe.json inside a Tandoor export. Ingredients are
+// attached to the steps that use them and times are in minutes.
+type tandoorRecipe struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Servings    float64 `json:"servings"`
+	WorkingTime int     `json:"working_time"`
+	WaitingTime int     `json:"waiting_time"`
+	Keywords    []struct {
+		Name string `json:"name"`
+	} `json:"keywords"`
+	Steps []struct {
+		Instruction string `json:"instruction"`
+		Ingredients []struct {
+			Food *struct {
+				Name string `json:"name"`
+			} `json:"food"`
+			Unit *struct {
+				Name string `json:"name"`
+			} `json:"unit"`
+			Amount       float64 `json:"amount"`
+			Note         string  `json:"note"`
+			IsHeader     bool    `json:"is_header"`
+			NoAmount     bool    `json:"no_amount"`
+			OriginalText string  `json:"original_text"`
+		} `json:"ingredients"`
+	} `json:"steps"`
+}
+
+// parseTandoor decodes a Tandoor recipe
+func parseTandoor(data []byte) (*Recipe, error) {
+	var t tandoorRecipe
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to decode Tandoor recipe: %w", err)
+	}
+
+	r := &Recipe{
+		Title:       unescapeText(t.Name),
+		Description: unescapeText(t.Description),
+		PrepTime:    time.Duration(t.WorkingTime) * time.Minute,
+		CookTime:    time.Duration(t.WaitingTime) * time.Minute,
+	}
+	for _, k := range t.Keywords {
+		if name := unescapeText(k.Name); name != "" {
+			r.Keywords = append(r.Keywords, name)
+		}
+	}
+	if t.Servings > 0 {
+		r.Servings = int(t.Servings)
+		r.Yield = strconv.Itoa(r.Servings)
+	}
+
+	for _, step := range t.Steps {
+		r.Instructions = append(r.Instructions, splitLines(step.Instruction)...)
+		for _, ing := range step.Ingredients {
+			if ing.IsHeader {
+				continue
+			}
+			if line := unescapeText(ing.OriginalText); line != "" {
+				r.Ingredients = append(r.Ingredients, line)
+				continue
+			}
+
+			var parts []string
+			if ing.Amount > 0 && !ing.NoAmount {
+				parts = append(parts, strconv.FormatFloat(ing.Amount, 'f', -1, 64))
+			}
+			if ing.Unit != nil && ing.Unit.Name != "" {
+				parts = append(parts, ing.Unit.Name)
+			}
+			if ing.Food != nil && ing.Food.Name != "" {
+				parts = append(parts, ing.Food.Name)
+			}
+			line := strings.Join(parts, " ")
+			if ing.Note != "" {
+				line += ", " + ing.Note
+			}
+			if line = unescapeText(line); line != "" {
+				r.Ingredients = append(r.Ingredients, line)
+			}
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,96 @@
+package recipe
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// nutritionProperties maps schema.org NutritionInformation properties onto
+// our Nutrition fields
+var nutritionProperties = []struct {
+	name  string
+	field func(*Nutrition) *string
+}{
+	{"calories", func(n *Nutrition) *string { return &n.Calories }},
+	{"fatContent", func(n *Nutrition) *string { return &n.Fat }},
+	{"saturatedFatContent", func(n *Nutrition) *string { return &n.SaturatedFat }},
+	{"carbohydrateContent", func(n *Nutrition) *string { return &n.Carbohydrate }},
+	{"sugarContent", func(n *Nutrition) *string { return &n.Sugar }},
+	{"fiberContent", func(n *Nutrition) *string { return &n.Fiber }},
+	{"proteinContent", func(n *Nutrition) *string { return &n.Protein }},
+	{"sodiumContent", func(n *Nutrition) *string { return &n.Sodium }},
+}
+
+// nutritionFrom builds Nutrition by looking up each schema.org property
+func nutritionFrom(lookup func(property string) string) Nutrition {
+	var n Nutrition
+	for _, p := range nutritionProperties {
+		*p.field(&n) = lookup(p.name)
+	}
+	return n
+}
+
+// splitKeywords splits comma-separated keyword lists, dropping duplicates
+func splitKeywords(values []string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		for _, k := range strings.Split(v, ",") {
+			k = cleanText(k)
+			if k == "" || seen[strings.ToLower(k)] {
+				continue
+			}
+			seen[strings.ToLower(k)] = true
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
+
+// dietNames turns schema.org RestrictedDiet values such as
+// "https://schema.org/GlutenFreeDiet" into readable names like "Gluten free"
+func dietNames(values []string) []string {
+	var diets []string
+	for _, v := range values {
+		if i := strings.LastIndexAny(v, ":/#"); i != -1 {
+			v = v[i+1:]
+		}
+		v = strings.TrimSuffix(v, "Diet")
+		if v == "" {
+			continue
+		}
+
+		var b strings.Builder
+		for i, r := range v {
+			if i > 0 && unicode.IsUpper(r) {
+				b.WriteRune(' ')
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		diets = append(diets, cleanText(b.String()))
+	}
+	return diets
+}
+
+// resolveURLs makes image and link URLs absolute against base, dropping any
+// that can't be parsed and any duplicates. base may be nil for local files.
+func resolveURLs(base *url.URL, refs []string) []string {
+	var resolved []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		u, err := url.Parse(strings.TrimSpace(ref))
+		if err != nil || ref == "" {
+			continue
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		if s := u.String(); !seen[s] {
+			seen[s] = true
+			resolved = append(resolved, s)
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,273 @@
+package recipe
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxMicrodataDepth bounds how deeply nested items are followed, guarding
+// against itemref cycles
+const maxMicrodataDepth = 8
+
+// MicrodataExtractor reads schema.org Recipe microdata (itemscope/itemprop)
+type MicrodataExtractor struct{}
+
+// Name implements the RecipeExtractor interface
+func (e *MicrodataExtractor) Name() string {
+	return "microdata"
+}
+
+// Extract implements the RecipeExtractor interface
+func (e *MicrodataExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	return newExtractions(e.Name(), extractMicrodata(doc), 0.95)
+}
+
+// microdataItem is an itemscope and the properties that belong to it. The
+// properties of items nested inside it belong to those items instead, so a
+// recipe's name is never confused with its author's.
+type microdataItem struct {
+	props map[string][]microdataValue
+}
+
+// microdataValue is a property value: text, or a nested item
+type microdataValue struct {
+	text string
+	item *microdataItem
+	sel  *goquery.Selection
+}
+
+// extractMicrodata maps each schema.org Recipe item on the page onto a Recipe
+func extractMicrodata(doc *goquery.Document) []*Recipe {
+	var recipes []*Recipe
+	doc.Find("[itemscope][itemtype]").Each(func(i int, s *goquery.Selection) {
+		if !isMicrodataRecipe(s) {
+			return
+		}
+		// A recipe inside another recipe is part of it, not a recipe of its own
+		if s.ParentsFiltered("[itemscope][itemtype]").FilterFunction(func(i int, p *goquery.Selection) bool {
+			return isMicrodataRecipe(p)
+		}).Length() > 0 {
+			return
+		}
+		recipes = append(recipes, recipeFromMicrodata(parseMicrodataItem(doc, s, 0)))
+	})
+	return recipes
+}
+
+// isMicrodataRecipe reports whether an itemscope is typed as a schema.org Recipe
+func isMicrodataRecipe(s *goquery.Selection) bool {
+	return hasRDFaTerm(s.AttrOr("itemtype", ""), "Recipe")
+}
+
+// parseMicrodataItem collects the properties of the item rooted at scope,
+// including any on elements it pulls in with itemref
+func parseMicrodataItem(doc *goquery.Document, scope *goquery.Selection, depth int) *microdataItem {
+	item := &microdataItem{
+		props: make(map[string][]microdataValue),
+	}
+	if depth > maxMicrodataDepth {
+		return item
+	}
+
+	item.collect(doc, scope, depth)
+
+	for _, id := range strings.Fields(scope.AttrOr("itemref", "")) {
+		ref := doc.Find("[id]").FilterFunction(func(i int, s *goquery.Selection) bool {
+			return s.AttrOr("id", "") == id
+		}).First()
+		if ref.Length() == 0 {
+			continue
+		}
+		item.add(doc, ref, depth)
+		if _, nested := ref.Attr("itemscope"); !nested {
+			item.collect(doc, ref, depth)
+		}
+	}
+
+	return item
+}
+
+// collect adds the properties found beneath s, stopping at nested items
+func (item *microdataItem) collect(doc *goquery.Document, s *goquery.Selection, depth int) {
+	s.Children().Each(func(i int, c *goquery.Selection) {
+		if hiddenElements[goquery.NodeName(c)] {
+			return
+		}
+		item.add(doc, c, depth)
+		// Properties inside a nested item belong to that item
+		if _, nested := c.Attr("itemscope"); nested {
+			return
+		}
+		item.collect(doc, c, depth)
+	})
+}
+
+// add records s as a value of each property it names, if any
+func (item *microdataItem) add(doc *goquery.Document, s *goquery.Selection, depth int) {
+	names := strings.Fields(s.AttrOr("itemprop", ""))
+	if len(names) == 0 {
+		return
+	}
+
+	value := microdataValue{sel: s}
+	if _, nested := s.Attr("itemscope"); nested {
+		value.item = parseMicrodataItem(doc, s, depth+1)
+	} else {
+		value.text = microdataText(s)
+	}
+
+	for _, name := range names {
+		// Properties may be given as full URLs, e.g. http://schema.org/name
+		if i := strings.LastIndexAny(name, "/#"); i != -1 {
+			name = name[i+1:]
+		}
+		item.props[name] = append(item.props[name], value)
+	}
+}
+
+// microdataText reads a property's value following the microdata rules:
+// machine-readable attributes first, then the element's visible text
+func microdataText(s *goquery.Selection) string {
+	if v, ok := s.Attr("content"); ok {
+		return cleanText(v)
+	}
+
+	attr := ""
+	switch goquery.NodeName(s) {
+	case "a", "area", "link":
+		attr = "href"
+	case "img", "audio", "video", "source", "embed", "iframe", "track":
+		attr = "src"
+	case "object":
+		attr = "data"
+	case "data", "meter":
+		attr = "value"
+	case "time":
+		attr = "datetime"
+	}
+	if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+		return cleanText(v)
+	}
+
+	return visibleText(s)
+}
+
+// text returns the first non-empty text value of any of the named properties
+func (item *microdataItem) text(names ...string) string {
+	for _, name := range names {
+		for _, v := range item.props[name] {
+			if v.item == nil && v.text != "" {
+				return v.text
+			}
+		}
+	}
+	return ""
+}
+
+// texts returns every non-empty text value of the named properties
+func (item *microdataItem) texts(names ...string) []string {
+	var result []string
+	for _, name := range names {
+		for _, v := range item.props[name] {
+			if v.item == nil && v.text != "" {
+				result = append(result, v.text)
+			}
+		}
+	}
+	return result
+}
+
+// instructions flattens recipeInstructions, which may be plain text, a list,
+// HowToStep items or HowToSection items holding further steps
+func (item *microdataItem) instructions() []string {
+	var steps []string
+	for _, v := range item.props["recipeInstructions"] {
+		steps = append(steps, microdataSteps(v)...)
+	}
+	return steps
+}
+
+// microdataSteps turns one instructions value into its steps
+func microdataSteps(v microdataValue) []string {
+	if v.item != nil {
+		if sub := v.item.props["itemListElement"]; len(sub) > 0 {
+			var steps []string
+			for _, s := range sub {
+				steps = append(steps, microdataSteps(s)...)
+			}
+			return steps
+		}
+		if text := v.item.text("text", "name"); text != "" {
+			return []string{text}
+		}
+		if text := visibleText(v.sel); text != "" {
+			return []string{text}
+		}
+		return nil
+	}
+
+	// A single element wrapping the whole method as a list
+	if items := v.sel.Find("li"); items.Length() > 0 {
+		return texts(items)
+	}
+	if v.text != "" {
+		return []string{v.text}
+	}
+	return nil
+}
+
+// nested returns the first nested item value of the named property, or an
+// empty item if there isn't one
+func (item *microdataItem) nested(name string) *microdataItem {
+	for _, v := range item.props[name] {
+		if v.item != nil {
+			return v.item
+		}
+	}
+	return &microdataItem{}
+}
+
+// images returns the image URLs, given either directly or as ImageObjects
+func (item *microdataItem) images() []string {
+	var urls []string
+	for _, v := range item.props["image"] {
+		if v.item != nil {
+			if u := v.item.text("url", "contentUrl"); u != "" {
+				urls = append(urls, u)
+			}
+		} else if v.text != "" {
+			urls = append(urls, v.text)
+		}
+	}
+	return urls
+}
+
+// recipeFromMicrodata maps a Recipe item onto our Recipe model
+func recipeFromMicrodata(item *microdataItem) *Recipe {
+	nutrition := item.nested("nutrition")
+
+	yield := item.text("recipeYield", "yield")
+	return &Recipe{
+		Title:           item.text("name"),
+		Description:     item.text("description"),
+		Ingredients:     item.texts("recipeIngredient", "ingredients"),
+		Instructions:    item.instructions(),
+		CookTime:        ParseDuration(item.text("cookTime")),
+		PrepTime:        ParseDuration(item.text("prepTime")),
+		TotalTime:       ParseDuration(item.text("totalTime")),
+		Yield:           yield,
+		Servings:        ParseServings(yield),
+		Cuisine:         strings.Join(item.texts("recipeCuisine"), ", "),
+		Language:        normaliseLanguage(item.text("inLanguage")),
+		Category:        item.texts("recipeCategory"),
+		Keywords:        splitKeywords(item.texts("keywords")),
+		CookingMethod:   strings.Join(item.texts("cookingMethod"), ", "),
+		SuitableForDiet: dietNames(item.texts("suitableForDiet")),
+		Nutrition: nutritionFrom(func(property string) string {
+			return nutrition.text(property)
+		}),
+		Images: item.images(),
+	}
+}
@@ -0,0 +1,96 @@
+package recipe
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureRecipe is the expected result stored alongside each HTML fixture,
+// with times written as Go duration strings to keep the files readable
+type fixtureRecipe struct {
+	Title        string   `json:"title"`
+	Ingredients  []string `json:"ingredients"`
+	Instructions []string `json:"instructions"`
+	CookTime     string   `json:"cook_time"`
+	PrepTime     string   `json:"prep_time"`
+	TotalTime    string   `json:"total_time"`
+	Yield        string   `json:"yield"`
+	Servings     int      `json:"servings"`
+	Cuisine      string   `json:"cuisine"`
+
+	Description     string    `json:"description"`
+	Category        []string  `json:"category"`
+	Keywords        []string  `json:"keywords"`
+	CookingMethod   string    `json:"cooking_method"`
+	SuitableForDiet []string  `json:"suitable_for_diet"`
+	Nutrition       Nutrition `json:"nutrition"`
+	Images          []string  `json:"images"`
+}
+
+func (f fixtureRecipe) recipe(t *testing.T) *Recipe {
+	t.Helper()
+	parse := func(s string) time.Duration {
+		if s == "" {
+			return 0
+		}
+		d, err := time.ParseDuration(s)
+		require.NoError(t, err)
+		return d
+	}
+	return &Recipe{
+		Title:        f.Title,
+		Ingredients:  f.Ingredients,
+		Instructions: f.Instructions,
+		CookTime:     parse(f.CookTime),
+		PrepTime:     parse(f.PrepTime),
+		TotalTime:    parse(f.TotalTime),
+		Yield:        f.Yield,
+		Servings:     f.Servings,
+		Cuisine:      f.Cuisine,
+
+		Description:     f.Description,
+		Category:        f.Category,
+		Keywords:        f.Keywords,
+		CookingMethod:   f.CookingMethod,
+		SuitableForDiet: f.SuitableForDiet,
+		Nutrition:       f.Nutrition,
+		Images:          f.Images,
+	}
+}
+
+func TestExtractMicrodata_Fixtures(t *testing.T) {
+	pages, err := filepath.Glob("testdata/microdata/*.html")
+	require.NoError(t, err)
+	require.NotEmpty(t, pages)
+
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+		t.Run(name, func(t *testing.T) {
+			html, err := os.ReadFile(page)
+			require.NoError(t, err)
+			data, err := os.ReadFile(strings.TrimSuffix(page, ".html") + ".json")
+			require.NoError(t, err)
+
+			var fixtures []fixtureRecipe
+			require.NoError(t, json.Unmarshal(data, &fixtures))
+			var expected []*Recipe
+			for _, f := range fixtures {
+				expected = append(expected, f.recipe(t))
+			}
+
+			assert.Equal(t, expected, extractMicrodata(newDoc(t, string(html))))
+		})
+	}
+}
+
+func TestVisibleText(t *testing.T) {
+	doc := newDoc(t, `<div id="x"><h2>Beef Stew</h2><p>By <b>Jane</b>&nbsp;Doe<script>track()</script></p><span hidden>secret</span></div>`)
+	assert.Equal(t, "Beef Stew By Jane Doe", visibleText(doc.Find("#x")))
+}
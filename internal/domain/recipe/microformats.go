@@ -0,0 +1,179 @@
+package recipe
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// MicroformatsExtractor reads microformats2 h-recipe markup (p-name,
+// p-ingredient, e-instructions, dt-duration and friends)
+type MicroformatsExtractor struct{}
+
+// Name implements the RecipeExtractor interface
+func (e *MicroformatsExtractor) Name() string {
+	return "h-recipe"
+}
+
+// Extract implements the RecipeExtractor interface
+func (e *MicroformatsExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	var recipes []*Recipe
+	doc.Find(".h-recipe").Each(func(i int, root *goquery.Selection) {
+		// An h-recipe nested in another one is part of it
+		if root.ParentsFiltered(".h-recipe").Length() > 0 {
+			return
+		}
+
+		yield := mfText(mfProperty(root, "p-yield").First())
+		recipes = append(recipes, &Recipe{
+			Title:        mfText(mfProperty(root, "p-name").First()),
+			Description:  mfText(mfProperty(root, "p-summary").First()),
+			Ingredients:  mfTexts(mfProperty(root, "p-ingredient")),
+			Instructions: mfLines(mfProperty(root, "e-instructions")),
+			TotalTime:    ParseDuration(mfDatetime(mfProperty(root, "dt-duration").First())),
+			Yield:        yield,
+			Servings:     ParseServings(yield),
+			Category:     mfTexts(mfProperty(root, "p-category")),
+			Images:       mfURLs(mfProperty(root, "u-photo")),
+		})
+	})
+
+	return newExtractions(e.Name(), recipes, 0.9)
+}
+
+// isMicroformatRoot reports whether an element is the root of a microformat,
+// e.g. an h-card for the recipe's author
+func isMicroformatRoot(s *goquery.Selection) bool {
+	for _, class := range strings.Fields(s.AttrOr("class", "")) {
+		if strings.HasPrefix(class, "h-") {
+			return true
+		}
+	}
+	return false
+}
+
+// mfProperty finds the elements in root with the given property class,
+// skipping those that belong to a nested microformat
+func mfProperty(root *goquery.Selection, class string) *goquery.Selection {
+	return root.Find("." + class).FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.ParentsUntilSelection(root).FilterFunction(func(i int, p *goquery.Selection) bool {
+			return isMicroformatRoot(p)
+		}).Length() == 0
+	})
+}
+
+// valueClass joins the parts of an element marked with the value class
+// pattern, reporting whether there were any
+func valueClass(s *goquery.Selection, read func(*goquery.Selection) string) (string, bool) {
+	parts := s.Find(".value")
+	if parts.Length() == 0 {
+		return "", false
+	}
+	var values []string
+	parts.Each(func(i int, part *goquery.Selection) {
+		values = append(values, read(part))
+	})
+	return cleanText(strings.Join(values, "")), true
+}
+
+// mfText reads a p-* property
+func mfText(s *goquery.Selection) string {
+	if s.Length() == 0 {
+		return ""
+	}
+	if v, ok := valueClass(s, mfText); ok {
+		return v
+	}
+
+	switch goquery.NodeName(s) {
+	case "abbr", "link":
+		if v, ok := s.Attr("title"); ok {
+			return cleanText(v)
+		}
+	case "data", "input":
+		if v, ok := s.Attr("value"); ok {
+			return cleanText(v)
+		}
+	case "img", "area":
+		if v, ok := s.Attr("alt"); ok {
+			return cleanText(v)
+		}
+	}
+	return visibleText(s)
+}
+
+// mfTexts reads every value of a p-* property
+func mfTexts(s *goquery.Selection) []string {
+	var result []string
+	s.Each(func(i int, el *goquery.Selection) {
+		if t := mfText(el); t != "" {
+			result = append(result, t)
+		}
+	})
+	return result
+}
+
+// mfDatetime reads a dt-* property
+func mfDatetime(s *goquery.Selection) string {
+	if s.Length() == 0 {
+		return ""
+	}
+	if v, ok := valueClass(s, mfDatetime); ok {
+		return v
+	}
+
+	attr := ""
+	switch goquery.NodeName(s) {
+	case "time", "ins", "del":
+		attr = "datetime"
+	case "abbr":
+		attr = "title"
+	case "data", "input":
+		attr = "value"
+	}
+	if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+		return cleanText(v)
+	}
+	return visibleText(s)
+}
+
+// mfURLs reads every value of a u-* property
+func mfURLs(s *goquery.Selection) []string {
+	var result []string
+	s.Each(func(i int, el *goquery.Selection) {
+		attr := ""
+		switch goquery.NodeName(el) {
+		case "a", "area", "link":
+			attr = "href"
+		case "img", "audio", "video", "source", "iframe":
+			attr = "src"
+		case "object":
+			attr = "data"
+		}
+		if v, ok := el.Attr(attr); ok && strings.TrimSpace(v) != "" {
+			result = append(result, strings.TrimSpace(v))
+		} else if t := mfText(el); t != "" {
+			result = append(result, t)
+		}
+	})
+	return result
+}
+
+// mfLines splits e-* properties holding HTML into lines, one per list item or
+// paragraph
+func mfLines(s *goquery.Selection) []string {
+	var lines []string
+	s.Each(func(i int, el *goquery.Selection) {
+		for _, selector := range []string{"li", "p"} {
+			if items := el.Find(selector); items.Length() > 0 {
+				lines = append(lines, texts(items)...)
+				return
+			}
+		}
+		if t := visibleText(el); t != "" {
+			lines = append(lines, t)
+		}
+	})
+	return lines
+}
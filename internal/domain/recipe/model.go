@@ -1,12 +1,122 @@
 package recipe
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type Recipe struct {
 	Title        string
+	Description  string
 	Ingredients  []string
 	Instructions []string
-	CookTime     string
-	PrepTime     string
-	TotalTime    string
-	Yield        string
+	CookTime     time.Duration
+	PrepTime     time.Duration
+	TotalTime    time.Duration
+	Yield        string // As published, e.g. "Serves 4-6"
+	Servings     int    // Parsed from Yield, 0 if unknown
 	Cuisine      string
+	Language     string // ISO 639-1 code, e.g. "fr", or empty if unknown
+
+	Category        []string // Course, e.g. "Main course", "Dessert"
+	Keywords        []string
+	CookingMethod   string   // As published, e.g. "Braising"
+	SuitableForDiet []string // e.g. "Vegetarian", "Gluten free"
+	Nutrition       Nutrition
+	Images          []string // Absolute URLs
+
+	Provenance Provenance
+
+	// LLMExtracted is set when no structured markup was found and the recipe
+	// was pulled out of the page text by the LLM, so may be less accurate
+	LLMExtracted bool
+
+	// TranslatedFrom is the language the recipe was translated into English
+	// from for pairing, if it was. The title is left as published.
+	TranslatedFrom string
+}
+
+// Nutrition holds per-serving nutrition facts as published, e.g. "12 g"
+type Nutrition struct {
+	Calories     string
+	Fat          string
+	SaturatedFat string
+	Carbohydrate string
+	Sugar        string
+	Fiber        string
+	Protein      string
+	Sodium       string
+}
+
+// Provenance records where a recipe came from
+type Provenance struct {
+	SourceURL    string // The URL or file the recipe was read from
+	CanonicalURL string // The page's own idea of its URL, if it declares one
+	AlternateURL string // The canonical, AMP or print version of the page the recipe was found on instead, if any
+	FetchedAt    time.Time
+	Extractor    string // The extractor that found the recipe, e.g. "json-ld"
+}
+
+// Duration returns the total time for the recipe, adding up prep and cook
+// time if no total was published
+func (r *Recipe) Duration() time.Duration {
+	if r.TotalTime > 0 {
+		return r.TotalTime
+	}
+	return r.PrepTime + r.CookTime
+}
+
+// IsZero reports whether no nutrition facts were published
+func (n Nutrition) IsZero() bool {
+	return n == Nutrition{}
+}
+
+// String summarises the nutrition facts most relevant to pairing, e.g.
+// "450 kcal, fat 20 g, sugar 5 g, sodium 800 mg"
+func (n Nutrition) String() string {
+	var parts []string
+	if n.Calories != "" {
+		parts = append(parts, n.Calories)
+	}
+	for _, fact := range []struct{ name, value string }{
+		{"fat", n.Fat},
+		{"saturated fat", n.SaturatedFat},
+		{"carbohydrate", n.Carbohydrate},
+		{"sugar", n.Sugar},
+		{"fibre", n.Fiber},
+		{"protein", n.Protein},
+		{"sodium", n.Sodium},
+	} {
+		if fact.value != "" {
+			parts = append(parts, fact.name+" "+fact.value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDishDetails summarises the course, cooking method, diets and
+// nutrition of the dish for the pairing prompt, or "unknown" if none of them
+// were published
+func (r *Recipe) FormatDishDetails() string {
+	var parts []string
+	if len(r.Category) > 0 {
+		parts = append(parts, "Course: "+strings.Join(r.Category, ", "))
+	}
+	if r.CookingMethod != "" {
+		parts = append(parts, "Cooking method: "+r.CookingMethod)
+	}
+	if len(r.SuitableForDiet) > 0 {
+		parts = append(parts, "Diet: "+strings.Join(r.SuitableForDiet, ", "))
+	}
+	if !r.Nutrition.IsZero() {
+		parts = append(parts, fmt.Sprintf("Nutrition per serving: %s", r.Nutrition))
+	}
+	if len(r.Keywords) > 0 {
+		parts = append(parts, "Keywords: "+strings.Join(r.Keywords, ", "))
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, "; ")
 }
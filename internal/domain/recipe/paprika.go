@@ -0,0 +1,64 @@
+package recipe
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// paprikaRecipe is a single recipe from a Paprika export. Each recipe in a
+// .paprikarecipes archive is a gzipped JSON document.
+type paprikaRecipe struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Ingredients string   `json:"ingredients"`
+	Directions  string   `json:"directions"`
+	Servings    string   `json:"servings"`
+	PrepTime    string   `json:"prep_time"`
+	CookTime    string   `json:"cook_time"`
+	TotalTime   string   `json:"total_time"`
+	Categories  []string `json:"categories"`
+	SourceURL   string   `json:"source_url"`
+	ImageURL    string   `json:"image_url"`
+}
+
+// parsePaprika decodes one gzipped Paprika recipe, counting it against im's
+// limits
+func parsePaprika(data []byte, im *importer) (*Recipe, error) {
+	// Be lenient with recipes that have already been decompressed
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress recipe: %w", err)
+		}
+		defer gz.Close()
+		if data, err = im.read(gz); err != nil {
+			return nil, fmt.Errorf("failed to decompress recipe: %w", err)
+		}
+	}
+
+	var p paprikaRecipe
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode recipe: %w", err)
+	}
+
+	r := &Recipe{
+		Title:        unescapeText(p.Name),
+		Description:  unescapeText(p.Description),
+		Ingredients:  splitLines(p.Ingredients),
+		Instructions: splitLines(p.Directions),
+		PrepTime:     ParseDuration(p.PrepTime),
+		CookTime:     ParseDuration(p.CookTime),
+		TotalTime:    ParseDuration(p.TotalTime),
+		Yield:        strings.TrimSpace(p.Servings),
+		Servings:     ParseServings(p.Servings),
+		Category:     nonEmpty(p.Categories),
+		Provenance:   Provenance{SourceURL: strings.TrimSpace(p.SourceURL)},
+	}
+	if u := strings.TrimSpace(p.ImageURL); u != "" {
+		r.Images = []string{u}
+	}
+	return r, nil
+}
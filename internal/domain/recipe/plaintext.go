@@ -0,0 +1,81 @@
+package recipe
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+`)
+	markdownEmphasis = regexp.MustCompile("\\*\\*|__|\\*|`")
+	markdownLink     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	listMarker       = regexp.MustCompile(`^\s*(?:[-*+•]|\d+[.)])\s+`)
+
+	ingredientsHeading  = regexp.MustCompile(`(?i)^(?:ingredients?|you(?:'ll)? need|shopping list)\b`)
+	instructionsHeading = regexp.MustCompile(`(?i)^(?:method|instructions?|directions?|steps|preparation)\b`)
+	otherHeading        = regexp.MustCompile(`(?i)^(?:notes?|tips?|serving suggestions?|nutrition|equipment)\b`)
+)
+
+// stripMarkdown removes Markdown formatting that would otherwise end up in
+// ingredient and instruction text, keeping headings on their own lines
+func stripMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = markdownHeading.ReplaceAllString(line, "")
+		line = markdownLink.ReplaceAllString(line, "$1")
+		line = markdownEmphasis.ReplaceAllString(line, "")
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseTextRecipe reads a recipe laid out in the conventional way: a title,
+// then an "Ingredients" section and a "Method" section. Returns nil if no
+// ingredients section can be found.
+func parseTextRecipe(text string) *Recipe {
+	const (
+		sectionNone = iota
+		sectionIngredients
+		sectionInstructions
+		sectionOther
+	)
+
+	r := &Recipe{}
+	section := sectionNone
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		heading := strings.TrimRight(line, ":")
+		switch {
+		case ingredientsHeading.MatchString(heading) && len(heading) < 40:
+			section = sectionIngredients
+			continue
+		case instructionsHeading.MatchString(heading) && len(heading) < 40:
+			section = sectionInstructions
+			continue
+		case otherHeading.MatchString(heading) && len(heading) < 40:
+			section = sectionOther
+			continue
+		}
+
+		item := cleanText(listMarker.ReplaceAllString(line, ""))
+		switch section {
+		case sectionNone:
+			if r.Title == "" {
+				r.Title = item
+			}
+		case sectionIngredients:
+			r.Ingredients = append(r.Ingredients, item)
+		case sectionInstructions:
+			r.Instructions = append(r.Instructions, item)
+		}
+	}
+
+	if r.Title == "" || len(r.Ingredients) == 0 {
+		return nil
+	}
+	return r
+}
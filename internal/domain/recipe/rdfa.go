@@ -0,0 +1,146 @@
+package recipe
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RDFaExtractor reads schema.org Recipes marked up with RDFa (typeof/property)
+type RDFaExtractor struct{}
+
+// Name implements the RecipeExtractor interface
+func (e *RDFaExtractor) Name() string {
+	return "rdfa"
+}
+
+// Extract implements the RecipeExtractor interface
+func (e *RDFaExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	var recipes []*Recipe
+	doc.Find("[typeof]").FilterFunction(func(i int, s *goquery.Selection) bool {
+		if !isRDFaRecipe(s) {
+			return false
+		}
+		// A recipe inside another recipe is part of it, not a recipe of its own
+		return s.ParentsFiltered("[typeof]").FilterFunction(func(i int, p *goquery.Selection) bool {
+			return isRDFaRecipe(p)
+		}).Length() == 0
+	}).Each(func(i int, scope *goquery.Selection) {
+		nutrition := rdfaProperty(scope, "nutrition").First()
+
+		yield := attrOrText(rdfaProperty(scope, "recipeYield").First())
+		recipes = append(recipes, &Recipe{
+			Title:           attrOrText(rdfaProperty(scope, "name").First()),
+			Description:     attrOrText(rdfaProperty(scope, "description").First()),
+			Ingredients:     rdfaTexts(rdfaProperty(scope, "recipeIngredient", "ingredients")),
+			Instructions:    rdfaInstructions(rdfaProperty(scope, "recipeInstructions")),
+			CookTime:        ParseDuration(attrOrText(rdfaProperty(scope, "cookTime").First())),
+			PrepTime:        ParseDuration(attrOrText(rdfaProperty(scope, "prepTime").First())),
+			TotalTime:       ParseDuration(attrOrText(rdfaProperty(scope, "totalTime").First())),
+			Yield:           yield,
+			Servings:        ParseServings(yield),
+			Cuisine:         strings.Join(rdfaTexts(rdfaProperty(scope, "recipeCuisine")), ", "),
+			Category:        rdfaTexts(rdfaProperty(scope, "recipeCategory")),
+			Keywords:        splitKeywords(rdfaTexts(rdfaProperty(scope, "keywords"))),
+			CookingMethod:   strings.Join(rdfaTexts(rdfaProperty(scope, "cookingMethod")), ", "),
+			SuitableForDiet: dietNames(rdfaResources(rdfaProperty(scope, "suitableForDiet"))),
+			Nutrition: nutritionFrom(func(property string) string {
+				if nutrition.Length() == 0 {
+					return ""
+				}
+				return attrOrText(rdfaProperty(nutrition, property).First())
+			}),
+			Images: rdfaResources(rdfaProperty(scope, "image")),
+		})
+	})
+
+	return newExtractions(e.Name(), recipes, 0.9)
+}
+
+// isRDFaRecipe reports whether an element's typeof names a schema.org Recipe
+func isRDFaRecipe(s *goquery.Selection) bool {
+	return hasRDFaTerm(s.AttrOr("typeof", ""), "Recipe")
+}
+
+// rdfaProperty finds the elements in scope whose property attribute names any
+// of the given schema.org terms. Properties of nested resources, such as the
+// name of the recipe's author, are skipped.
+func rdfaProperty(scope *goquery.Selection, names ...string) *goquery.Selection {
+	return scope.Find("[property]").FilterFunction(func(i int, s *goquery.Selection) bool {
+		if s.ParentsUntilSelection(scope).Filter("[typeof]").Length() > 0 {
+			return false
+		}
+		for _, name := range names {
+			if hasRDFaTerm(s.AttrOr("property", ""), name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// rdfaTexts returns the non-empty value of every element in the selection
+func rdfaTexts(s *goquery.Selection) []string {
+	var result []string
+	s.Each(func(i int, el *goquery.Selection) {
+		if t := attrOrText(el); t != "" {
+			result = append(result, t)
+		}
+	})
+	return result
+}
+
+// rdfaResources returns the IRIs that elements in the selection point to,
+// falling back to their text for literal values
+func rdfaResources(s *goquery.Selection) []string {
+	var result []string
+	s.Each(func(i int, el *goquery.Selection) {
+		for _, attr := range []string{"resource", "href", "src", "content"} {
+			if v, ok := el.Attr(attr); ok && strings.TrimSpace(v) != "" {
+				result = append(result, strings.TrimSpace(v))
+				return
+			}
+		}
+		if t := visibleText(el); t != "" {
+			result = append(result, t)
+		}
+	})
+	return result
+}
+
+// rdfaInstructions reads recipeInstructions, which may be plain text, a list
+// or HowToStep resources with their own text property
+func rdfaInstructions(s *goquery.Selection) []string {
+	var steps []string
+	s.Each(func(i int, el *goquery.Selection) {
+		if _, ok := el.Attr("typeof"); ok {
+			if text := attrOrText(rdfaProperty(el, "text").First()); text != "" {
+				steps = append(steps, text)
+				return
+			}
+		}
+		if items := el.Find("li"); items.Length() > 0 {
+			steps = append(steps, texts(items)...)
+			return
+		}
+		if text := attrOrText(el); text != "" {
+			steps = append(steps, text)
+		}
+	})
+	return steps
+}
+
+// hasRDFaTerm reports whether a space-separated RDFa attribute value contains
+// the given term, either bare, prefixed (schema:Recipe) or as a full IRI
+func hasRDFaTerm(value, term string) bool {
+	for _, v := range strings.Fields(value) {
+		if i := strings.LastIndexAny(v, ":/#"); i != -1 {
+			v = v[i+1:]
+		}
+		if v == term {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package recipe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// TextExtractorName identifies recipes parsed from plain text or Markdown in
+// their Provenance
+const TextExtractorName = "text"
+
+// Format identifies the kind of document a recipe is read from
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+)
+
+// DetectFormat works out the format of a recipe document from its file name,
+// sniffing the content when the name is empty or has an unknown extension
+func DetectFormat(name string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".html", ".htm", ".xhtml":
+		return FormatHTML
+	case ".json", ".jsonld":
+		return FormatJSON
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".txt":
+		return FormatText
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	lower := bytes.ToLower(trimmed[:min(len(trimmed), 512)])
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")), bytes.HasPrefix(trimmed, []byte("[")):
+		return FormatJSON
+	case bytes.HasPrefix(lower, []byte("<!doctype html")), bytes.Contains(lower, []byte("<html")),
+		bytes.Contains(lower, []byte("<body")), bytes.Contains(lower, []byte("<script")):
+		return FormatHTML
+	case bytes.HasPrefix(trimmed, []byte("#")):
+		return FormatMarkdown
+	}
+	return FormatText
+}
+
+// ReadRecipes extracts every recipe from a local document such as a saved web
+// page, a JSON-LD file or a Markdown note. name is used to detect the format
+// and may be empty, e.g. when reading from stdin.
+func (s *Service) ReadRecipes(ctx context.Context, r io.Reader, name string) ([]*Recipe, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipe: %w", err)
+	}
+
+	var recipes []*Recipe
+	var lang string
+	switch DetectFormat(name, data) {
+	case FormatHTML:
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		if recipes, err = s.extract(ctx, doc, nil); err != nil {
+			return nil, err
+		}
+		lang = pageLanguage(doc)
+		// Saved pages usually still say where they came from
+		if canonical := canonicalURL(doc, nil); canonical != nil {
+			for _, r := range recipes {
+				r.Provenance.CanonicalURL = canonical.String()
+				r.Images = resolveURLs(canonical, r.Images)
+			}
+		}
+	case FormatJSON:
+		recipes = parseJSONLD(string(data))
+		if len(recipes) == 0 {
+			return nil, fmt.Errorf("%w in JSON-LD", ErrNoRecipe)
+		}
+		for _, r := range recipes {
+			r.Provenance.Extractor = (&JSONLDExtractor{}).Name()
+		}
+	case FormatMarkdown:
+		if recipes, err = s.extractText(ctx, stripMarkdown(string(data))); err != nil {
+			return nil, err
+		}
+	default:
+		if recipes, err = s.extractText(ctx, string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range recipes {
+		r.Provenance.SourceURL = name
+		setLanguage(r, lang)
+	}
+	return recipes, nil
+}
+
+// ReadRecipe is like ReadRecipes but returns only the first recipe
+func (s *Service) ReadRecipe(ctx context.Context, r io.Reader, name string) (*Recipe, error) {
+	recipes, err := s.ReadRecipes(ctx, r, name)
+	if err != nil {
+		return nil, err
+	}
+	return recipes[0], nil
+}
+
+// extract runs the extractor chain over an HTML document, falling back to the
+// LLM if configured. pageURL may be nil for local documents.
+func (s *Service) extract(ctx context.Context, doc *goquery.Document, pageURL *url.URL) ([]*Recipe, error) {
+	recipes, err := s.extractStructured(doc, pageURL)
+	if err == nil || s.fallback == nil {
+		return recipes, err
+	}
+
+	return oneRecipe(s.fallback.Extract(ctx, doc))
+}
+
+// extractStructured runs the extractor chain over an HTML document, without
+// the LLM fallback
+func (s *Service) extractStructured(doc *goquery.Document, pageURL *url.URL) ([]*Recipe, error) {
+	extractions, err := runExtractors(s.extractors, doc, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*Recipe, len(extractions))
+	for i, e := range extractions {
+		recipes[i] = e.Recipe
+		recipes[i].Provenance.Extractor = e.Extractor
+	}
+	return recipes, nil
+}
+
+// extractText parses a plain text recipe, falling back to the LLM if the text
+// doesn't follow the usual title/ingredients/method layout
+func (s *Service) extractText(ctx context.Context, text string) ([]*Recipe, error) {
+	if r := parseTextRecipe(text); r != nil {
+		r.Provenance.Extractor = TextExtractorName
+		return []*Recipe{r}, nil
+	}
+
+	if s.fallback == nil {
+		return nil, ErrNoRecipe
+	}
+
+	return oneRecipe(s.fallback.ExtractText(ctx, strings.TrimSpace(text)))
+}
+
+// oneRecipe wraps the result of an extraction that finds a single recipe
+func oneRecipe(r *Recipe, err error) ([]*Recipe, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []*Recipe{r}, nil
+}
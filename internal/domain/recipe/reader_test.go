@@ -0,0 +1,114 @@
+package recipe
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		data     string
+		expected Format
+	}{
+		{name: "html extension", file: "stew.html", data: "", expected: FormatHTML},
+		{name: "json-ld extension", file: "stew.jsonld", data: "", expected: FormatJSON},
+		{name: "markdown extension", file: "notes/stew.md", data: "", expected: FormatMarkdown},
+		{name: "text extension", file: "stew.txt", data: "<html>", expected: FormatText},
+		{name: "sniffed html", file: "", data: "<!DOCTYPE html><html></html>", expected: FormatHTML},
+		{name: "sniffed json", file: "", data: "  {\"@type\": \"Recipe\"}", expected: FormatJSON},
+		{name: "sniffed markdown", file: "", data: "# Beef Stew\n", expected: FormatMarkdown},
+		{name: "plain text", file: "", data: "Beef Stew\nIngredients\n", expected: FormatText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectFormat(tt.file, []byte(tt.data)))
+		})
+	}
+}
+
+func TestService_ReadRecipe(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		data     string
+		expected *Recipe
+	}{
+		{
+			name: "saved web page",
+			file: "stew.html",
+			data: `<html><script type="application/ld+json">{"@type": "Recipe", "name": "Beef Stew", "recipeIngredient": ["1 kg beef"]}</script></html>`,
+			expected: &Recipe{
+				Title:       "Beef Stew",
+				Ingredients: []string{"1 kg beef"},
+				Provenance:  Provenance{SourceURL: "stew.html", Extractor: "json-ld"},
+			},
+		},
+		{
+			name: "JSON-LD file",
+			file: "stew.json",
+			data: `{"@type": "Recipe", "name": "Beef Stew", "recipeIngredient": ["1 kg beef"]}`,
+			expected: &Recipe{
+				Title:       "Beef Stew",
+				Ingredients: []string{"1 kg beef"},
+				Provenance:  Provenance{SourceURL: "stew.json", Extractor: "json-ld"},
+			},
+		},
+		{
+			name: "markdown note",
+			file: "stew.md",
+			data: "# Beef **Stew**\n\nMum's recipe.\n\n## Ingredients\n\n- 1 kg [beef shin](https://example.com)\n* 2 carrots\n\n## Method\n\n1. Brown the beef.\n2. Simmer.\n\n## Notes\n\nFreezes well.\n",
+			expected: &Recipe{
+				Title:        "Beef Stew",
+				Ingredients:  []string{"1 kg beef shin", "2 carrots"},
+				Instructions: []string{"Brown the beef.", "Simmer."},
+				Provenance:   Provenance{SourceURL: "stew.md", Extractor: TextExtractorName},
+			},
+		},
+		{
+			name: "pasted text from stdin",
+			file: "",
+			data: "Beef Stew\n\nIngredients:\n1 kg beef\n2 carrots\n\nDirections:\nBrown the beef, then simmer.\n",
+			expected: &Recipe{
+				Title:        "Beef Stew",
+				Ingredients:  []string{"1 kg beef", "2 carrots"},
+				Instructions: []string{"Brown the beef, then simmer."},
+				Provenance:   Provenance{Extractor: TextExtractorName},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService().ReadRecipe(context.Background(), strings.NewReader(tt.data), tt.file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestService_ReadRecipe_Canonical(t *testing.T) {
+	data := `<html><head><link rel="canonical" href="https://example.com/recipes/stew"></head>
+		<script type="application/ld+json">{"@type": "Recipe", "name": "Beef Stew", "image": ["/img/stew.jpg", {"@type": "ImageObject", "url": "https://cdn.example.com/stew.jpg"}]}</script></html>`
+
+	got, err := NewService().ReadRecipe(context.Background(), strings.NewReader(data), "stew.html")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/recipes/stew", got.Provenance.CanonicalURL)
+	assert.Equal(t, []string{"https://example.com/img/stew.jpg", "https://cdn.example.com/stew.jpg"}, got.Images)
+}
+
+func TestService_ReadRecipe_LLMFallback(t *testing.T) {
+	llm := &mockLLMClient{response: `{"title": "Stew", "ingredients": ["beef"], "instructions": []}`}
+	service := NewService().WithLLMFallback(NewLLMExtractor(llm, &mockPromptGenerator{}))
+
+	got, err := service.ReadRecipe(context.Background(), strings.NewReader("Just brown some beef and stew it for ages.\n"), "")
+	require.NoError(t, err)
+	assert.True(t, got.LLMExtracted)
+	assert.Equal(t, "Just brown some beef and stew it for ages.", llm.prompt)
+}
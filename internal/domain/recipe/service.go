@@ -1,64 +1,167 @@
 package recipe
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+	"github.com/kieranajp/pairings/internal/infrastructure/fetcher"
 )
 
+// RecipesNamespace is the cache namespace holding extracted recipes
+const RecipesNamespace = "recipes"
+
+// recipeCacheVersion is part of every recipe cache key. Bump it when
+// extraction changes so stale results aren't served.
+const recipeCacheVersion = "5"
+
 type Service struct {
-	client *http.Client
+	fetcher    fetcher.Fetcher
+	extractors []RecipeExtractor
+	fallback   *LLMExtractor
+	cache      *cache.Store
 }
 
 func NewService() *Service {
 	return &Service{
-		client: &http.Client{},
+		fetcher:    fetcher.NewPoliteDecorator(fetcher.NewHTTPFetcher(fetcher.DefaultConfig()), fetcher.DefaultHostLimiter, ""),
+		extractors: DefaultExtractors(nil),
 	}
 }
 
-func (s *Service) GetRecipe(ctx context.Context, url string) (*Recipe, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// WithFetcher replaces the fetcher used to download recipe pages
+func (s *Service) WithFetcher(f fetcher.Fetcher) *Service {
+	s.fetcher = f
+	return s
+}
+
+// WithExtractors replaces the extractor chain, which is tried in order
+func (s *Service) WithExtractors(extractors ...RecipeExtractor) *Service {
+	s.extractors = extractors
+	return s
+}
+
+// WithLLMFallback enables LLM extraction for pages that no extractor in the
+// chain can handle
+func (s *Service) WithLLMFallback(fallback *LLMExtractor) *Service {
+	s.fallback = fallback
+	return s
+}
+
+// WithCache stores extracted recipes so that unchanged pages don't go through
+// extraction, and in particular the LLM fallback, again
+func (s *Service) WithCache(store *cache.Store) *Service {
+	s.cache = store
+	return s
+}
 
-	resp, err := s.client.Do(req)
+// GetRecipes fetches a page and extracts every recipe on it. Most pages have
+// just the one, but round-ups can have dozens.
+func (s *Service) GetRecipes(ctx context.Context, rawURL string) ([]*Recipe, error) {
+	page, err := s.fetcher.Fetch(ctx, rawURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch recipe: %w", err)
 	}
-	defer resp.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	key := recipeCacheKey(page)
+	if s.cache != nil {
+		var cached []*Recipe
+		if found, err := s.cache.Get(RecipesNamespace, key, &cached); err == nil && found && len(cached) > 0 {
+			return cached, nil
+		}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	r := &Recipe{}
+	// Pages that hide their markup often expose it on another version of the
+	// page, which is cheaper and more reliable to try than the LLM
+	recipes, err := s.extractStructured(doc, page.URL)
+	found := page
+	if errors.Is(err, ErrNoRecipe) {
+		var alternate *fetcher.Page
+		if recipes, alternate, err = s.extractAlternates(ctx, doc, page.URL); err == nil {
+			found = alternate
+		}
+	}
+	if errors.Is(err, ErrNoRecipe) && s.fallback != nil {
+		recipes, err = oneRecipe(s.fallback.Extract(ctx, doc))
+	}
+	if errors.Is(err, ErrNoRecipe) {
+		return nil, fmt.Errorf("%w at URL", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract recipe: %w", err)
+	}
 
-	// Extract recipe data using Schema.org markup
-	doc.Find("[itemtype='http://schema.org/Recipe']").Each(func(i int, s *goquery.Selection) {
-		r.Title = s.Find("[itemprop='name']").Text()
-		r.CookTime = s.Find("[itemprop='cookTime']").Text()
-		r.PrepTime = s.Find("[itemprop='prepTime']").Text()
-		r.TotalTime = s.Find("[itemprop='totalTime']").Text()
-		r.Yield = s.Find("[itemprop='recipeYield']").Text()
-		r.Cuisine = s.Find("[itemprop='recipeCuisine']").Text()
+	canonical := canonicalURL(doc, page.URL)
+	lang := pageLanguage(doc)
+	for _, r := range recipes {
+		setLanguage(r, lang)
+		r.Provenance.SourceURL = rawURL
+		r.Provenance.FetchedAt = found.FetchedAt
+		if canonical != nil {
+			r.Provenance.CanonicalURL = canonical.String()
+		}
+		if found != page {
+			r.Provenance.AlternateURL = found.URL.String()
+		}
+		r.Images = resolveURLs(found.URL, r.Images)
+	}
 
-		s.Find("[itemprop='recipeIngredient']").Each(func(i int, s *goquery.Selection) {
-			r.Ingredients = append(r.Ingredients, strings.TrimSpace(s.Text()))
-		})
+	if s.cache != nil {
+		// Failing to cache shouldn't fail the request
+		_ = s.cache.Put(RecipesNamespace, key, recipes)
+	}
+	return recipes, nil
+}
 
-		s.Find("[itemprop='recipeInstructions']").Each(func(i int, s *goquery.Selection) {
-			r.Instructions = append(r.Instructions, strings.TrimSpace(s.Text()))
-		})
-	})
+// canonicalURL returns the URL a page declares as its canonical address, from
+// <link rel="canonical"> or og:url, or nil if it doesn't declare one. base
+// resolves relative links and may be nil.
+func canonicalURL(doc *goquery.Document, base *url.URL) *url.URL {
+	href := doc.Find("link[rel~='canonical']").AttrOr("href", "")
+	if strings.TrimSpace(href) == "" {
+		href = doc.Find("meta[property='og:url']").AttrOr("content", "")
+	}
+	if strings.TrimSpace(href) == "" {
+		return nil
+	}
 
-	if r.Title == "" {
-		return nil, fmt.Errorf("no recipe found at URL")
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return nil
 	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if !u.IsAbs() {
+		return nil
+	}
+	return u
+}
+
+// GetRecipe is like GetRecipes but returns only the first recipe on the page
+func (s *Service) GetRecipe(ctx context.Context, rawURL string) (*Recipe, error) {
+	recipes, err := s.GetRecipes(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return recipes[0], nil
+}
 
-	return r, nil
+// recipeCacheKey identifies an extracted recipe by the page it came from and
+// the content of that page, so an edited recipe is extracted afresh
+func recipeCacheKey(page *fetcher.Page) string {
+	sum := sha256.Sum256(page.Body)
+	return recipeCacheVersion + " " + page.URL.String() + " " + hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,166 @@
+package recipe
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+	"github.com/kieranajp/pairings/internal/infrastructure/fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFetcher serves a fixed body for every URL
+type stubFetcher struct {
+	body string
+}
+
+func (f *stubFetcher) Fetch(ctx context.Context, rawURL string, header http.Header) (*fetcher.Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &fetcher.Page{URL: u, StatusCode: http.StatusOK, Body: []byte(f.body)}, nil
+}
+
+func TestService_GetRecipe_Cache(t *testing.T) {
+	llm := &mockLLMClient{
+		response: `{"title": "Ragù", "ingredients": ["500g beef mince"], "instructions": ["Simmer."]}`,
+	}
+	page := &stubFetcher{body: `<html><body><article><p>Nonna's ragù, simmered all day.</p></article></body></html>`}
+
+	service := NewService().
+		WithFetcher(page).
+		WithLLMFallback(NewLLMExtractor(llm, &mockPromptGenerator{})).
+		WithCache(cache.NewStore(t.TempDir()))
+
+	first, err := service.GetRecipe(context.Background(), "https://example.com/ragu")
+	require.NoError(t, err)
+	second, err := service.GetRecipe(context.Background(), "https://example.com/ragu")
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, llm.calls, "cached recipe should skip the LLM")
+
+	// An edited page is extracted again
+	page.body = `<html><body><article><p>Nonna's ragù, now with pork.</p></article></body></html>`
+	_, err = service.GetRecipe(context.Background(), "https://example.com/ragu")
+	require.NoError(t, err)
+	assert.Equal(t, 2, llm.calls)
+}
+
+// routeFetcher serves a body per URL, 404ing the rest, and records the URLs
+// it was asked for
+type routeFetcher struct {
+	pages     map[string]string
+	requested []string
+}
+
+func (f *routeFetcher) Fetch(ctx context.Context, rawURL string, header http.Header) (*fetcher.Page, error) {
+	f.requested = append(f.requested, rawURL)
+	body, ok := f.pages[rawURL]
+	if !ok {
+		return nil, &fetcher.StatusError{URL: rawURL, StatusCode: http.StatusNotFound}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &fetcher.Page{URL: u, StatusCode: http.StatusOK, Body: []byte(body)}, nil
+}
+
+func TestService_GetRecipe_Alternates(t *testing.T) {
+	const recipeJSONLD = `<script type="application/ld+json">{"@type": "Recipe", "name": "Beef Stew", "recipeIngredient": ["1 kg beef"], "image": "stew.jpg"}</script>`
+	const pageURL = "https://example.com/recipes/stew?utm_source=feed"
+
+	tests := []struct {
+		name          string
+		pages         map[string]string
+		wantAlternate string
+		wantRequested []string
+	}{
+		{
+			name: "canonical",
+			pages: map[string]string{
+				pageURL: `<html><head>
+					<link rel="canonical" href="https://example.com/recipes/stew">
+					<link rel="amphtml" href="https://example.com/amp/recipes/stew">
+				</head><body><div id="app"></div></body></html>`,
+				"https://example.com/recipes/stew":     recipeJSONLD,
+				"https://example.com/amp/recipes/stew": recipeJSONLD,
+			},
+			wantAlternate: "https://example.com/recipes/stew",
+			wantRequested: []string{pageURL, "https://example.com/recipes/stew"},
+		},
+		{
+			name: "AMP after a canonical without markup",
+			pages: map[string]string{
+				pageURL: `<html><head>
+					<link rel="canonical" href="https://example.com/recipes/stew">
+					<link rel="amphtml" href="/amp/recipes/stew">
+				</head></html>`,
+				"https://example.com/recipes/stew":     `<html><body>Loading…</body></html>`,
+				"https://example.com/amp/recipes/stew": recipeJSONLD,
+			},
+			wantAlternate: "https://example.com/amp/recipes/stew",
+			wantRequested: []string{pageURL, "https://example.com/recipes/stew", "https://example.com/amp/recipes/stew"},
+		},
+		{
+			name: "linked print view",
+			pages: map[string]string{
+				pageURL: `<html><body><a href="/wprm_print/beef-stew/print/">Print recipe</a></body></html>`,
+				"https://example.com/wprm_print/beef-stew/print/": recipeJSONLD,
+			},
+			wantAlternate: "https://example.com/wprm_print/beef-stew/print/",
+			wantRequested: []string{pageURL, "https://example.com/wprm_print/beef-stew/print/"},
+		},
+		{
+			name: "print=1 when there is no /print/ view",
+			pages: map[string]string{
+				pageURL: `<html><body></body></html>`,
+				"https://example.com/recipes/stew?print=1&utm_source=feed": recipeJSONLD,
+			},
+			wantAlternate: "https://example.com/recipes/stew?print=1&utm_source=feed",
+			wantRequested: []string{
+				pageURL,
+				"https://example.com/recipes/stew/print/?utm_source=feed",
+				"https://example.com/recipes/stew?print=1&utm_source=feed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &routeFetcher{pages: tt.pages}
+			service := NewService().WithFetcher(f)
+
+			got, err := service.GetRecipe(context.Background(), pageURL)
+			require.NoError(t, err)
+			assert.Equal(t, "Beef Stew", got.Title)
+			assert.Equal(t, pageURL, got.Provenance.SourceURL)
+			assert.Equal(t, tt.wantAlternate, got.Provenance.AlternateURL)
+			assert.Equal(t, tt.wantRequested, f.requested)
+
+			alternate, _ := url.Parse(tt.wantAlternate)
+			image, _ := url.Parse("stew.jpg")
+			assert.Equal(t, []string{alternate.ResolveReference(image).String()}, got.Images, "images resolve against the alternate")
+		})
+	}
+}
+
+func TestService_GetRecipe_NoAlternateHasRecipe(t *testing.T) {
+	f := &routeFetcher{pages: map[string]string{
+		"https://example.com/stew": `<html><head><link rel="canonical" href="https://example.com/stew"></head></html>`,
+	}}
+
+	_, err := NewService().WithFetcher(f).GetRecipe(context.Background(), "https://example.com/stew")
+	assert.ErrorIs(t, err, ErrNoRecipe)
+	// The page names itself as canonical, so only the print views are tried
+	assert.Equal(t, []string{
+		"https://example.com/stew",
+		"https://example.com/stew/print/",
+		"https://example.com/stew?print=1",
+	}, f.requested)
+}
@@ -0,0 +1,147 @@
+package recipe
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteRule holds the CSS selectors used to pull a recipe out of one site's pages
+type SiteRule struct {
+	Title        string `yaml:"title"`
+	Ingredients  string `yaml:"ingredients"`
+	Instructions string `yaml:"instructions"`
+	CookTime     string `yaml:"cook_time"`
+	PrepTime     string `yaml:"prep_time"`
+	TotalTime    string `yaml:"total_time"`
+	Yield        string `yaml:"yield"`
+	Cuisine      string `yaml:"cuisine"`
+}
+
+// SiteRules maps a hostname to the selectors for that site
+type SiteRules map[string]SiteRule
+
+// ParseSiteRules parses site rules from YAML, rejecting any selector that
+// isn't valid CSS
+func ParseSiteRules(data []byte) (SiteRules, error) {
+	var rules SiteRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse site rules: %w", err)
+	}
+	for host, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid site rule for %s: %w", host, err)
+		}
+	}
+	return rules, nil
+}
+
+// validate checks that every selector in the rule compiles
+func (r SiteRule) validate() error {
+	selectors := []struct{ field, selector string }{
+		{"title", r.Title},
+		{"ingredients", r.Ingredients},
+		{"instructions", r.Instructions},
+		{"cook_time", r.CookTime},
+		{"prep_time", r.PrepTime},
+		{"total_time", r.TotalTime},
+		{"yield", r.Yield},
+		{"cuisine", r.Cuisine},
+	}
+	for _, s := range selectors {
+		if s.selector == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(s.selector); err != nil {
+			return fmt.Errorf("%s selector %q: %w", s.field, s.selector, err)
+		}
+	}
+	return nil
+}
+
+// LoadSiteRules reads site rules from a YAML file
+func LoadSiteRules(path string) (SiteRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read site rules: %w", err)
+	}
+	return ParseSiteRules(data)
+}
+
+// lookup finds the rule for a host, ignoring a leading "www."
+func (r SiteRules) lookup(host string) (SiteRule, bool) {
+	host = strings.ToLower(host)
+	if rule, ok := r[host]; ok {
+		return rule, true
+	}
+	rule, ok := r[strings.TrimPrefix(host, "www.")]
+	return rule, ok
+}
+
+// SiteRulesExtractor applies per-host CSS selector rules. Its results rank
+// above the generic extractors', which only fill in what the rules leave out.
+type SiteRulesExtractor struct {
+	rules SiteRules
+}
+
+// NewSiteRulesExtractor creates an extractor for the given rules
+func NewSiteRulesExtractor(rules SiteRules) *SiteRulesExtractor {
+	return &SiteRulesExtractor{
+		rules: rules,
+	}
+}
+
+// Name implements the RecipeExtractor interface
+func (e *SiteRulesExtractor) Name() string {
+	return "site-rules"
+}
+
+// siteSpecific marks the extractor as a siteSpecificExtractor
+func (e *SiteRulesExtractor) siteSpecific() {}
+
+// Extract implements the RecipeExtractor interface
+func (e *SiteRulesExtractor) Extract(doc *goquery.Document, pageURL *url.URL) ([]*Extraction, error) {
+	if pageURL == nil {
+		return nil, ErrNoRecipe
+	}
+	rule, ok := e.rules.lookup(pageURL.Hostname())
+	if !ok {
+		return nil, ErrNoRecipe
+	}
+
+	yield := selectOne(doc, rule.Yield)
+	r := &Recipe{
+		Title:        selectOne(doc, rule.Title),
+		Ingredients:  selectAll(doc, rule.Ingredients),
+		Instructions: selectAll(doc, rule.Instructions),
+		CookTime:     ParseDuration(selectOne(doc, rule.CookTime)),
+		PrepTime:     ParseDuration(selectOne(doc, rule.PrepTime)),
+		TotalTime:    ParseDuration(selectOne(doc, rule.TotalTime)),
+		Yield:        yield,
+		Servings:     ParseServings(yield),
+		Cuisine:      selectOne(doc, rule.Cuisine),
+	}
+
+	return single(newExtraction(e.Name(), r, 0.9))
+}
+
+// selectOne returns the value of the first element matching selector
+func selectOne(doc *goquery.Document, selector string) string {
+	if selector == "" {
+		return ""
+	}
+	return attrOrText(doc.Find(selector).First())
+}
+
+// selectAll returns the text of every element matching selector
+func selectAll(doc *goquery.Document, selector string) []string {
+	if selector == "" {
+		return nil
+	}
+	return texts(doc.Find(selector))
+}
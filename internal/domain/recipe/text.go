@@ -0,0 +1,119 @@
+package recipe
+
+import (
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// stripTags turns block-level HTML into newlines and drops any other markup,
+// for sites that embed HTML inside their JSON-LD strings
+func stripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return html.UnescapeString(s)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	doc.Find("br, p, li, div").Each(func(i int, sel *goquery.Selection) {
+		sel.AppendHtml("\n")
+	})
+	return doc.Text()
+}
+
+// cleanText collapses runs of whitespace. Text from the DOM has already had
+// its entities decoded by the parser, so they aren't decoded again here.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// unescapeText decodes HTML entities and collapses runs of whitespace, for
+// text that wasn't parsed from the DOM, such as JSON-LD values, which
+// publishers often HTML-escape
+func unescapeText(s string) string {
+	return cleanText(html.UnescapeString(s))
+}
+
+// hiddenElements never contribute to the visible text of a page
+var hiddenElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+	"template": true,
+	"svg":      true,
+}
+
+// blockElements break up the words either side of them, so that
+// "<h2>Beef Stew</h2><p>By Jane</p>" doesn't read as "Beef StewBy Jane"
+var blockElements = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "article": true, "header": true, "footer": true, "aside": true,
+	"table": true, "tr": true, "td": true, "th": true, "dl": true, "dt": true, "dd": true,
+	"blockquote": true, "figure": true, "figcaption": true, "pre": true,
+}
+
+// visibleText returns the text a reader would see in s: scripts, styles and
+// hidden elements are dropped, block elements separate their words and
+// whitespace is collapsed
+func visibleText(s *goquery.Selection) string {
+	var b strings.Builder
+
+	var walk func(*goquery.Selection)
+	walk = func(sel *goquery.Selection) {
+		sel.Contents().Each(func(i int, c *goquery.Selection) {
+			name := goquery.NodeName(c)
+			switch {
+			case name == "#text":
+				b.WriteString(c.Nodes[0].Data)
+			case strings.HasPrefix(name, "#"), hiddenElements[name], isHidden(c):
+				// Comments and anything the reader can't see
+			case blockElements[name]:
+				b.WriteString(" ")
+				walk(c)
+				b.WriteString(" ")
+			default:
+				walk(c)
+			}
+		})
+	}
+	walk(s)
+
+	return cleanText(b.String())
+}
+
+// isHidden reports whether an element is hidden from readers by its attributes
+func isHidden(s *goquery.Selection) bool {
+	if _, ok := s.Attr("hidden"); ok {
+		return true
+	}
+	if s.AttrOr("aria-hidden", "") == "true" {
+		return true
+	}
+	style := strings.ToLower(strings.Join(strings.Fields(s.AttrOr("style", "")), ""))
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// attrOrText reads the machine-readable value of an element if it has one
+// (content, datetime), falling back to its visible text
+func attrOrText(s *goquery.Selection) string {
+	for _, attr := range []string{"content", "datetime"} {
+		if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+			return cleanText(v)
+		}
+	}
+	return visibleText(s)
+}
+
+// texts returns the visible, non-empty text of every element in the selection
+func texts(s *goquery.Selection) []string {
+	var result []string
+	s.Each(func(i int, el *goquery.Selection) {
+		if t := visibleText(el); t != "" {
+			result = append(result, t)
+		}
+	})
+	return result
+}
@@ -0,0 +1,132 @@
+package recipe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
+)
+
+// TranslationsNamespace is the cache namespace holding recipe translations
+const TranslationsNamespace = "translations"
+
+// translationCacheVersion is part of every translation cache key. Bump it
+// when the translation prompt changes.
+const translationCacheVersion = "1"
+
+// TranslationPromptGenerator builds the prompt used to translate recipes
+type TranslationPromptGenerator interface {
+	GenerateRecipeTranslationPrompt(language, recipeJSON string) (string, error)
+}
+
+// Translator asks a language model to translate a recipe's ingredients,
+// method and dish details into English, so that the pairing prompt and the
+// flavour profile work from ingredient names they recognise
+type Translator struct {
+	llm       client.LLMClient
+	promptGen TranslationPromptGenerator
+	cache     *cache.Store
+}
+
+// translatable mirrors config/translation_schema.json. It holds the parts of
+// a recipe that are translated; the title is deliberately left out.
+type translatable struct {
+	Description   string   `json:"description,omitempty"`
+	Ingredients   []string `json:"ingredients"`
+	Instructions  []string `json:"instructions"`
+	Cuisine       string   `json:"cuisine,omitempty"`
+	Category      []string `json:"category,omitempty"`
+	CookingMethod string   `json:"cooking_method,omitempty"`
+}
+
+// NewTranslator creates a new translator. llm should validate responses
+// against the translation schema.
+func NewTranslator(llm client.LLMClient, promptGen TranslationPromptGenerator) *Translator {
+	return &Translator{
+		llm:       llm,
+		promptGen: promptGen,
+	}
+}
+
+// WithCache stores translations so that the same recipe isn't translated twice
+func (t *Translator) WithCache(store *cache.Store) *Translator {
+	t.cache = store
+	return t
+}
+
+// Translate returns a copy of r with its ingredients, method and dish details
+// in English. The title is kept as published. Recipes already in English, or
+// whose language is unknown, are returned as they are.
+func (t *Translator) Translate(ctx context.Context, r *Recipe) (*Recipe, error) {
+	if r.Language == "" || r.Language == English {
+		return r, nil
+	}
+
+	source := translatable{
+		Description:   r.Description,
+		Ingredients:   append([]string{}, r.Ingredients...),
+		Instructions:  append([]string{}, r.Instructions...),
+		Cuisine:       r.Cuisine,
+		Category:      r.Category,
+		CookingMethod: r.CookingMethod,
+	}
+	input, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode recipe: %w", err)
+	}
+
+	key := translationCacheKey(r.Language, input)
+	var translated translatable
+	found := false
+	if t.cache != nil {
+		found, _ = t.cache.Get(TranslationsNamespace, key, &translated)
+	}
+
+	if !found {
+		prompt, err := t.promptGen.GenerateRecipeTranslationPrompt(LanguageName(r.Language), string(input))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate prompt: %w", err)
+		}
+
+		response, err := t.llm.Complete(ctx, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate recipe: %w", err)
+		}
+		if err := json.Unmarshal([]byte(response), &translated); err != nil {
+			return nil, fmt.Errorf("failed to decode translated recipe: %w", err)
+		}
+
+		// Ingredients are translated line by line, so a different count means
+		// the model merged, split or dropped some
+		if len(translated.Ingredients) != len(r.Ingredients) {
+			return nil, fmt.Errorf("translation has %d ingredients, expected %d", len(translated.Ingredients), len(r.Ingredients))
+		}
+
+		if t.cache != nil {
+			// Failing to cache shouldn't fail the request
+			_ = t.cache.Put(TranslationsNamespace, key, translated)
+		}
+	}
+
+	out := *r
+	out.Description = translated.Description
+	out.Ingredients = translated.Ingredients
+	out.Instructions = translated.Instructions
+	out.Cuisine = translated.Cuisine
+	out.Category = translated.Category
+	out.CookingMethod = translated.CookingMethod
+	out.TranslatedFrom = r.Language
+	out.Language = English
+	return &out, nil
+}
+
+// translationCacheKey identifies a translation by the source language and
+// the text being translated
+func translationCacheKey(language string, input []byte) string {
+	sum := sha256.Sum256(input)
+	return translationCacheVersion + " " + language + " " + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTranslationPromptGenerator passes the recipe JSON straight through as
+// the prompt
+type mockTranslationPromptGenerator struct{}
+
+func (m *mockTranslationPromptGenerator) GenerateRecipeTranslationPrompt(language, recipeJSON string) (string, error) {
+	return language + ": " + recipeJSON, nil
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	french := &Recipe{
+		Title:        "Bœuf bourguignon",
+		Ingredients:  []string{"1 kg de bœuf", "75 cl de vin rouge"},
+		Instructions: []string{"Faire mariner le bœuf."},
+		Cuisine:      "Française",
+		Language:     "fr",
+	}
+
+	tests := []struct {
+		name     string
+		recipe   *Recipe
+		response string
+		err      error
+		expected *Recipe
+		wantErr  bool
+	}{
+		{
+			name:     "translates all but the title",
+			recipe:   french,
+			response: `{"ingredients": ["1 kg beef", "75 cl red wine"], "instructions": ["Marinate the beef."], "cuisine": "French"}`,
+			expected: &Recipe{
+				Title:          "Bœuf bourguignon",
+				Ingredients:    []string{"1 kg beef", "75 cl red wine"},
+				Instructions:   []string{"Marinate the beef."},
+				Cuisine:        "French",
+				Language:       "en",
+				TranslatedFrom: "fr",
+			},
+		},
+		{
+			name:     "English is left alone",
+			recipe:   &Recipe{Title: "Beef Stew", Ingredients: []string{"1 kg beef"}, Language: "en"},
+			expected: &Recipe{Title: "Beef Stew", Ingredients: []string{"1 kg beef"}, Language: "en"},
+		},
+		{
+			name:     "unknown language is left alone",
+			recipe:   &Recipe{Title: "Beef Stew", Ingredients: []string{"1 kg beef"}},
+			expected: &Recipe{Title: "Beef Stew", Ingredients: []string{"1 kg beef"}},
+		},
+		{
+			name:     "ingredients lost in translation",
+			recipe:   french,
+			response: `{"ingredients": ["1 kg beef braised in red wine"], "instructions": []}`,
+			wantErr:  true,
+		},
+		{
+			name:    "LLM error",
+			recipe:  french,
+			err:     errors.New("quota exceeded"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mockLLMClient{response: tt.response, err: tt.err}
+			translator := NewTranslator(llm, &mockTranslationPromptGenerator{})
+
+			got, err := translator.Translate(context.Background(), tt.recipe)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	assert.Equal(t, []string{"1 kg de bœuf", "75 cl de vin rouge"}, french.Ingredients, "the original recipe is untouched")
+}
+
+func TestTranslator_Cache(t *testing.T) {
+	llm := &mockLLMClient{response: `{"ingredients": ["2 onions"], "instructions": ["Slice the onions."]}`}
+	translator := NewTranslator(llm, &mockTranslationPromptGenerator{}).
+		WithCache(cache.NewStore(t.TempDir()))
+
+	r := &Recipe{Title: "Soupe à l'oignon", Ingredients: []string{"2 oignons"}, Instructions: []string{"Émincer les oignons."}, Language: "fr"}
+	for i := 0; i < 2; i++ {
+		got, err := translator.Translate(context.Background(), r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2 onions"}, got.Ingredients)
+		assert.Equal(t, "Soupe à l'oignon", got.Title)
+	}
+	assert.Equal(t, 1, llm.calls, "second translation served from the cache")
+	assert.Contains(t, llm.prompt, "French: ")
+}
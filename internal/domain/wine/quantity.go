@@ -0,0 +1,20 @@
+package wine
+
+const (
+	// BottleSize is the volume of a standard bottle in millilitres
+	BottleSize = 750
+	// GlassSize is the volume of a generous glass in millilitres
+	GlassSize = 150
+	// GlassesPerServing is how many glasses to allow each diner with a meal
+	GlassesPerServing = 2
+)
+
+// BottlesFor returns how many standard bottles to buy for the given number of
+// servings, rounding up. Returns 0 if servings is unknown.
+func BottlesFor(servings int) int {
+	if servings <= 0 {
+		return 0
+	}
+	total := servings * GlassesPerServing * GlassSize
+	return (total + BottleSize - 1) / BottleSize
+}
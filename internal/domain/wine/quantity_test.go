@@ -0,0 +1,25 @@
+package wine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBottlesFor(t *testing.T) {
+	tests := []struct {
+		servings int
+		expected int
+	}{
+		{servings: 0, expected: 0},
+		{servings: 1, expected: 1},
+		{servings: 2, expected: 1},
+		{servings: 4, expected: 2},
+		{servings: 6, expected: 3},
+		{servings: 10, expected: 4},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, BottlesFor(tt.servings), "servings: %d", tt.servings)
+	}
+}
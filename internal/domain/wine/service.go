@@ -19,6 +19,7 @@ type Service struct {
 	llm       client.LLMClient
 	promptGen prompt.Generator
 	log       logger.Logger
+	onChunk   func(chunk string)
 }
 
 // NewService creates a new wine service
@@ -34,6 +35,13 @@ func NewService(
 	}
 }
 
+// WithStream passes the recommendations to onChunk as they are generated.
+// GetRecommendations still returns them once they are complete and valid.
+func (s *Service) WithStream(onChunk func(chunk string)) *Service {
+	s.onChunk = onChunk
+	return s
+}
+
 // GetRecommendations gets wine recommendations based on preferences
 func (s *Service) GetRecommendations(
 	ctx context.Context,
@@ -84,7 +92,12 @@ func (s *Service) GetRecommendations(
 	s.log.Debug().Str("prompt", prompt).Msg("Generated prompt")
 
 	// Get recommendations from LLM
-	recommendations, err := s.llm.Complete(ctx, prompt)
+	var recommendations string
+	if s.onChunk != nil {
+		recommendations, err = client.Stream(ctx, s.llm, prompt, s.onChunk)
+	} else {
+		recommendations, err = s.llm.Complete(ctx, prompt)
+	}
 	if err != nil {
 		s.log.Error().Err(err).Msg("Failed to get recommendations")
 		return "", fmt.Errorf("failed to get recommendations: %w", err)
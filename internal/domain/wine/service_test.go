@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/kieranajp/pairings/internal/domain/recipe"
+	"github.com/kieranajp/pairings/internal/infrastructure/client"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,7 +17,7 @@ type mockLLMClient struct {
 	mock.Mock
 }
 
-func (m *mockLLMClient) Complete(ctx context.Context, prompt string) (string, error) {
+func (m *mockLLMClient) Complete(ctx context.Context, prompt string, opts ...client.Option) (string, error) {
 	args := m.Called(ctx, prompt)
 	return args.String(0), args.Error(1)
 }
@@ -39,6 +40,16 @@ func (m *mockPromptGenerator) GenerateWinePairingPrompt(r *recipe.Recipe) (strin
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockPromptGenerator) GenerateRecipeExtractionPrompt(text string) (string, error) {
+	args := m.Called(text)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockPromptGenerator) GenerateRecipeTranslationPrompt(language, recipeJSON string) (string, error) {
+	args := m.Called(language, recipeJSON)
+	return args.String(0), args.Error(1)
+}
+
 // mockLogger is a mock implementation of logger.Logger
 type mockLogger struct {
 	mock.Mock
@@ -67,6 +78,14 @@ func (m *mockLogger) Debug() *zerolog.Event {
 	return args.Get(0).(*zerolog.Event)
 }
 
+func (m *mockLogger) Warn() *zerolog.Event {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return m.logger.Warn()
+	}
+	return args.Get(0).(*zerolog.Event)
+}
+
 func (m *mockLogger) Error() *zerolog.Event {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -227,3 +246,29 @@ func TestService_GetRecommendations(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetRecommendations_Stream(t *testing.T) {
+	llm := new(mockLLMClient)
+	promptGen := new(mockPromptGenerator)
+	log := newMockLogger()
+
+	promptGen.On("GenerateWineRecommendationPrompt",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	).Return("test prompt", nil)
+	// The mock can't stream, so the whole response arrives as one chunk
+	llm.On("Complete", mock.Anything, "test prompt").Return("test recommendations", nil)
+	log.On("Info").Return(nil)
+	log.On("Debug").Return(nil)
+
+	var chunks []string
+	service := NewService(llm, promptGen, log).WithStream(func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+
+	got, err := service.GetRecommendations(context.Background(), "steak", 20, 50, "USD", "", "", nil, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test recommendations", got)
+	assert.Equal(t, []string{"test recommendations"}, chunks)
+	llm.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName is the directory created under the user cache dir
+const appName = "pairings"
+
+// DefaultDir returns the cache directory under the user's cache dir, e.g.
+// ~/.cache/pairings on Linux
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache dir: %w", err)
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// Store is a simple disk-backed key/value store. Values are JSON encoded and
+// grouped into namespaces, one directory each.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir. The directory is created lazily.
+func NewStore(dir string) *Store {
+	return &Store{
+		dir: dir,
+	}
+}
+
+// Dir returns the root directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// path returns the file holding a key. Keys are hashed so that any string
+// (such as a URL) can be used safely.
+func (s *Store) path(namespace, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, namespace, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get decodes the value stored under key into v, reporting whether it was found
+func (s *Store) Get(namespace, key string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(s.path(namespace, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		// A corrupt entry is as good as a missing one
+		return false, nil
+	}
+	return true, nil
+}
+
+// Put stores v under key, replacing any existing value
+func (s *Store) Put(namespace, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	path := s.path(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	// Write to a temp file and rename so readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the value stored under key, if any
+func (s *Store) Delete(namespace, key string) error {
+	err := os.Remove(s.path(namespace, key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache entry: %w", err)
+	}
+	return nil
+}
+
+// Clear removes every entry in the given namespaces, or the whole store if
+// none are given
+func (s *Store) Clear(namespaces ...string) error {
+	if len(namespaces) == 0 {
+		if err := os.RemoveAll(s.dir); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		return nil
+	}
+
+	for _, ns := range namespaces {
+		if err := os.RemoveAll(filepath.Join(s.dir, ns)); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+	}
+	return nil
+}
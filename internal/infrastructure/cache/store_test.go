@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	Name  string
+	Count int
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "cache"))
+
+	var got entry
+	found, err := store.Get("pages", "https://example.com/", &got)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, store.Put("pages", "https://example.com/", entry{Name: "stew", Count: 2}))
+	require.NoError(t, store.Put("recipes", "https://example.com/", entry{Name: "recipe"}))
+
+	found, err = store.Get("pages", "https://example.com/", &got)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, entry{Name: "stew", Count: 2}, got)
+
+	require.NoError(t, store.Clear("pages"))
+	found, _ = store.Get("pages", "https://example.com/", &got)
+	assert.False(t, found)
+	found, _ = store.Get("recipes", "https://example.com/", &got)
+	assert.True(t, found)
+
+	require.NoError(t, store.Delete("recipes", "https://example.com/"))
+	found, _ = store.Get("recipes", "https://example.com/", &got)
+	assert.False(t, found)
+
+	require.NoError(t, store.Clear())
+	_, err = os.Stat(store.Dir())
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
+)
+
+const (
+	anthropicBaseURL          = "https://api.anthropic.com/v1"
+	defaultAnthropicModel     = "claude-sonnet-4-5"
+	defaultAnthropicMaxTokens = 4096
+	anthropicVersion          = "2023-06-01"
+
+	// statusOverloaded is the non-standard status Anthropic uses when its
+	// API is overloaded
+	statusOverloaded = 529
+)
+
+// anthropicSystemPrompt keeps the model to the JSON the prompts ask for. The
+// Messages API has no JSON mode, and without this the model tends to
+// introduce its answer or explain it afterwards.
+const anthropicSystemPrompt = "Respond only with JSON that matches the schema in the request: no introduction, no explanation after it and no Markdown code fences."
+
+// AnthropicClient talks to the Anthropic Messages API
+type AnthropicClient struct {
+	apiKey string
+	model  string
+	system string
+	usage  UsageRecorder
+	client HTTPClient
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model         string             `json:"model"`
+	MaxTokens     int                `json:"max_tokens"`
+	System        string             `json:"system,omitempty"`
+	Messages      []anthropicMessage `json:"messages"`
+	Temperature   *float64           `json:"temperature,omitempty"`
+	TopP          *float64           `json:"top_p,omitempty"`
+	TopK          *int               `json:"top_k,omitempty"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewAnthropicClient creates a new Messages API client with the given API key
+// and model
+func NewAnthropicClient(apiKey, model string) *AnthropicClient {
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+
+	return &AnthropicClient{
+		apiKey: apiKey,
+		model:  model,
+		system: anthropicSystemPrompt,
+		client: &http.Client{},
+	}
+}
+
+// WithUsage records the tokens used by each request with recorder
+func (c *AnthropicClient) WithUsage(recorder UsageRecorder) *AnthropicClient {
+	c.usage = recorder
+	return c
+}
+
+// Complete implements the LLMClient interface. A system instruction is added
+// after the JSON-only one, and WithSeed is ignored as the Messages API has no
+// seed. Newer models reject temperature and top_p together, so WithTopP is
+// dropped when WithTemperature is also given. The API requires a token
+// limit, so responses are capped at 4096 tokens unless WithMaxTokens is given.
+func (c *AnthropicClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	options := NewOptions(opts...)
+	reqBody := anthropicRequest{
+		Model:         c.model,
+		MaxTokens:     defaultAnthropicMaxTokens,
+		System:        c.system,
+		Messages:      []anthropicMessage{{Role: "user", Content: prompt}},
+		Temperature:   options.Temperature,
+		TopP:          options.TopP,
+		TopK:          options.TopK,
+		StopSequences: options.StopSequences,
+	}
+	if options.MaxTokens > 0 {
+		reqBody.MaxTokens = options.MaxTokens
+	}
+	if reqBody.Temperature != nil {
+		reqBody.TopP = nil
+	}
+	if options.SystemInstruction != "" {
+		reqBody.System = strings.TrimSpace(c.system + "\n\n" + options.SystemInstruction)
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", anthropicBaseURL+"/messages", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", c.apiKey)
+	req.Header.Set("Anthropic-Version", anthropicVersion)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", anthropicStatusError(resp)
+	}
+
+	var response anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	// Truncated responses are still paid for
+	recordUsage(c.usage, c.model, usage.Tokens{
+		Prompt:     response.Usage.InputTokens,
+		Completion: response.Usage.OutputTokens,
+	})
+
+	// Partial JSON would only fail validation, so say why it's partial
+	if response.StopReason == "max_tokens" {
+		return "", fmt.Errorf("response truncated at %d tokens, raise the max tokens", reqBody.MaxTokens)
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return text.String(), nil
+}
+
+// anthropicStatusError describes a failed request, wrapping ErrRateLimited
+// or ErrOverloaded where they apply so callers can tell them apart
+func anthropicStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	message := string(body)
+	var apiErr anthropicError
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+		message = apiErr.Error.Message
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || apiErr.Error.Type == "rate_limit_error":
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			return fmt.Errorf("%w (retry after %ss): %s", ErrRateLimited, retryAfter, message)
+		}
+		return fmt.Errorf("%w: %s", ErrRateLimited, message)
+	case resp.StatusCode == statusOverloaded || apiErr.Error.Type == "overloaded_error":
+		return fmt.Errorf("%w: %s", ErrOverloaded, message)
+	default:
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, message)
+	}
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewAnthropicClient(t *testing.T) {
+	client := NewAnthropicClient("test-key", "")
+	if client.model != defaultAnthropicModel {
+		t.Errorf("NewAnthropicClient() model = %v, want %v", client.model, defaultAnthropicModel)
+	}
+}
+
+func TestAnthropicClient_Complete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResponse   string
+		mockStatusCode int
+		mockHeader     http.Header
+		mockErr        error
+		wantResponse   string
+		wantErr        string
+		wantErrIs      error
+	}{
+		{
+			name:           "successful response",
+			mockResponse:   `{"content":[{"type":"text","text":"[{\"name\":"},{"type":"text","text":"\"Rioja\"}]"}],"stop_reason":"end_turn"}`,
+			mockStatusCode: http.StatusOK,
+			wantResponse:   `[{"name":"Rioja"}]`,
+		},
+		{
+			name:           "rate limited",
+			mockResponse:   `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`,
+			mockStatusCode: http.StatusTooManyRequests,
+			mockHeader:     http.Header{"Retry-After": []string{"30"}},
+			wantErr:        "retry after 30s",
+			wantErrIs:      ErrRateLimited,
+		},
+		{
+			name:           "overloaded",
+			mockResponse:   `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			mockStatusCode: statusOverloaded,
+			wantErr:        "Overloaded",
+			wantErrIs:      ErrOverloaded,
+		},
+		{
+			name:           "other API error",
+			mockResponse:   `{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: Field required"}}`,
+			mockStatusCode: http.StatusBadRequest,
+			wantErr:        "status 400: max_tokens: Field required",
+		},
+		{
+			name:    "HTTP client error",
+			mockErr: errors.New("connection error"),
+			wantErr: "failed to send request",
+		},
+		{
+			name:           "truncated response",
+			mockResponse:   `{"content":[{"type":"text","text":"[{\"name\":"}],"stop_reason":"max_tokens"}`,
+			mockStatusCode: http.StatusOK,
+			wantErr:        "response truncated",
+		},
+		{
+			name:           "empty response",
+			mockResponse:   `{"content":[],"stop_reason":"end_turn"}`,
+			mockStatusCode: http.StatusOK,
+			wantErr:        "no response from model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewAnthropicClient("test-key", "claude-test")
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					header := tt.mockHeader
+					if header == nil {
+						header = http.Header{}
+					}
+					return &http.Response{
+						StatusCode: tt.mockStatusCode,
+						Header:     header,
+						Body:       &mockReadCloser{strings.NewReader(tt.mockResponse)},
+					}, nil
+				},
+			}
+
+			got, err := client.Complete(context.Background(), "test prompt")
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("AnthropicClient.Complete() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("AnthropicClient.Complete() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AnthropicClient.Complete() unexpected error: %v", err)
+			}
+			if got != tt.wantResponse {
+				t.Errorf("AnthropicClient.Complete() = %v, want %v", got, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestAnthropicClient_Complete_RequestValidation(t *testing.T) {
+	client := NewAnthropicClient("test-key", "claude-test")
+	client.client = &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.String() != "https://api.anthropic.com/v1/messages" {
+				t.Errorf("unexpected URL %s", req.URL)
+			}
+			if got := req.Header.Get("X-Api-Key"); got != "test-key" {
+				t.Errorf("x-api-key = %q, want test-key", got)
+			}
+			if got := req.Header.Get("Anthropic-Version"); got != anthropicVersion {
+				t.Errorf("anthropic-version = %q, want %q", got, anthropicVersion)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			var sent anthropicRequest
+			if err := json.Unmarshal(body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if sent.Model != "claude-test" || sent.MaxTokens != defaultAnthropicMaxTokens {
+				t.Errorf("model = %q, max_tokens = %d", sent.Model, sent.MaxTokens)
+			}
+			if !strings.Contains(sent.System, "only with JSON") {
+				t.Errorf("system prompt should ask for JSON only, got %q", sent.System)
+			}
+			if len(sent.Messages) != 1 || sent.Messages[0].Role != "user" || sent.Messages[0].Content != "test prompt" {
+				t.Errorf("unexpected messages %+v", sent.Messages)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       &mockReadCloser{strings.NewReader(`{"content":[{"type":"text","text":"{}"}],"stop_reason":"end_turn"}`)},
+			}, nil
+		},
+	}
+
+	if _, err := client.Complete(context.Background(), "test prompt"); err != nil {
+		t.Errorf("AnthropicClient.Complete() unexpected error: %v", err)
+	}
+}
+
+func TestAnthropicClient_Complete_Options(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		want          string
+		wantMaxTokens int
+	}{
+		{
+			name:          "temperature takes precedence over top_p",
+			opts:          []Option{WithTemperature(0.3), WithTopP(0.8), WithTopK(20), WithStopSequences("STOP")},
+			want:          `"temperature":0.3,"top_k":20,"stop_sequences":["STOP"]`,
+			wantMaxTokens: defaultAnthropicMaxTokens,
+		},
+		{
+			name:          "top_p on its own",
+			opts:          []Option{WithTopP(0.8)},
+			want:          `"top_p":0.8`,
+			wantMaxTokens: defaultAnthropicMaxTokens,
+		},
+		{
+			name:          "max tokens replace the default",
+			opts:          []Option{WithMaxTokens(1024), WithTopK(20)},
+			want:          `"top_k":20`,
+			wantMaxTokens: 1024,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewAnthropicClient("test-key", "claude-test")
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, err := io.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("failed to read request body: %v", err)
+					}
+					if !strings.HasSuffix(string(body), `}],`+tt.want+`}`) {
+						t.Errorf("request body = %s, want it to end with %s", body, tt.want)
+					}
+					var sent anthropicRequest
+					if err := json.Unmarshal(body, &sent); err != nil {
+						t.Fatalf("failed to decode request body: %v", err)
+					}
+					if sent.MaxTokens != tt.wantMaxTokens {
+						t.Errorf("max_tokens = %d, want %d", sent.MaxTokens, tt.wantMaxTokens)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       &mockReadCloser{strings.NewReader(`{"content":[{"type":"text","text":"{}"}],"stop_reason":"end_turn"}`)},
+					}, nil
+				},
+			}
+
+			if _, err := client.Complete(context.Background(), "test prompt", tt.opts...); err != nil {
+				t.Errorf("AnthropicClient.Complete() unexpected error: %v", err)
+			}
+		})
+	}
+}
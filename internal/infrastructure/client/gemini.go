@@ -1,17 +1,25 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
 )
 
 const (
 	baseURL      = "https://generativelanguage.googleapis.com/v1beta"
 	defaultModel = "gemini-2.0-flash"
+
+	// maxStreamEventSize is the largest server-sent event Stream will read
+	maxStreamEventSize = 1 << 20
 )
 
 // HTTPClient is an interface for making HTTP requests
@@ -20,27 +28,66 @@ type HTTPClient interface {
 }
 
 type GeminiClient struct {
-	apiKey string
-	model  string
-	client HTTPClient
+	apiKey    string
+	model     string
+	schema    *geminiSchema
+	schemaErr error
+	json      bool
+	usage     UsageRecorder
+	client    HTTPClient
 }
 
 type geminiRequest struct {
-	Contents []struct {
-		Parts []struct {
-			Text string `json:"text"`
-		} `json:"parts"`
-	} `json:"contents"`
+	Contents          []geminiContent         `json:"contents"`
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiGenerationConfig struct {
+	ResponseMimeType string        `json:"responseMimeType,omitempty"`
+	ResponseSchema   *geminiSchema `json:"responseSchema,omitempty"`
+	Temperature      *float64      `json:"temperature,omitempty"`
+	TopP             *float64      `json:"topP,omitempty"`
+	TopK             *int          `json:"topK,omitempty"`
+	MaxOutputTokens  int           `json:"maxOutputTokens,omitempty"`
+	StopSequences    []string      `json:"stopSequences,omitempty"`
+	Seed             *int64        `json:"seed,omitempty"`
 }
 
 type geminiResponse struct {
 	Candidates []struct {
-		Content struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-		} `json:"content"`
+		Content geminiContent `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata *geminiUsageMetadata `json:"usageMetadata"`
+}
+
+type geminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	ThoughtsTokenCount   int `json:"thoughtsTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+// tokens converts the metadata, which may be missing, into a usage count.
+// Thinking models report their thoughts separately from the response, but
+// they are billed as output, so they count as completion tokens.
+func (m *geminiUsageMetadata) tokens() usage.Tokens {
+	if m == nil {
+		return usage.Tokens{}
+	}
+	return usage.Tokens{
+		Prompt:     m.PromptTokenCount,
+		Completion: m.CandidatesTokenCount + m.ThoughtsTokenCount,
+		Total:      m.TotalTokenCount,
+	}
 }
 
 // NewGeminiClient creates a new Gemini client with the given API key and model
@@ -56,57 +103,158 @@ func NewGeminiClient(apiKey string, model string) *GeminiClient {
 	}
 }
 
+// WithUsage records the tokens used by each request with recorder
+func (c *GeminiClient) WithUsage(recorder UsageRecorder) *GeminiClient {
+	c.usage = recorder
+	return c
+}
+
+// WithSchema implements the StructuredOutputClient interface. Responses are
+// requested as JSON, constrained by the schema translated into Gemini's
+// OpenAPI subset. A schema that can't be translated makes every request
+// fail rather than quietly go unconstrained.
+func (c *GeminiClient) WithSchema(schema string) LLMClient {
+	structured := *c
+	structured.json = true
+	structured.schema, structured.schemaErr = newGeminiSchema(schema)
+	return &structured
+}
+
+// generationConfig maps the options and schema onto Gemini's generationConfig,
+// returning nil when there is nothing to set
+func (c *GeminiClient) generationConfig(o Options) *geminiGenerationConfig {
+	config := geminiGenerationConfig{
+		Temperature:     o.Temperature,
+		TopP:            o.TopP,
+		TopK:            o.TopK,
+		MaxOutputTokens: o.MaxTokens,
+		StopSequences:   o.StopSequences,
+		Seed:            o.Seed,
+	}
+	if c.json {
+		config.ResponseMimeType = "application/json"
+		config.ResponseSchema = c.schema
+	}
+
+	if reflect.ValueOf(config).IsZero() {
+		return nil
+	}
+	return &config
+}
+
 // Complete implements the LLMClient interface
-func (c *GeminiClient) Complete(ctx context.Context, prompt string) (string, error) {
+func (c *GeminiClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
 	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", baseURL, c.model, c.apiKey)
 
+	resp, err := c.send(ctx, url, prompt, opts)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var response geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	recordUsage(c.usage, c.model, response.UsageMetadata.tokens())
+
+	if len(response.Candidates) == 0 || len(response.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return response.Candidates[0].Content.Parts[0].Text, nil
+}
+
+// Stream implements the StreamingClient interface using server-sent events.
+// Each event holds a response like Complete's with the next piece of text.
+func (c *GeminiClient) Stream(ctx context.Context, prompt string, onChunk func(chunk string), opts ...Option) (string, error) {
+	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", baseURL, c.model, c.apiKey)
+
+	resp, err := c.send(ctx, url, prompt, opts)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// Every event carries the usage so far, so the last one has the total
+	var full strings.Builder
+	var tokens usage.Tokens
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamEventSize)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		var event geminiResponse
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return "", fmt.Errorf("failed to decode stream event: %w", err)
+		}
+		if event.UsageMetadata != nil {
+			tokens = event.UsageMetadata.tokens()
+		}
+		if len(event.Candidates) == 0 {
+			continue
+		}
+		for _, part := range event.Candidates[0].Content.Parts {
+			if part.Text == "" {
+				continue
+			}
+			full.WriteString(part.Text)
+			onChunk(part.Text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read stream: %w", err)
+	}
+	recordUsage(c.usage, c.model, tokens)
+
+	if full.Len() == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return full.String(), nil
+}
+
+// send posts the prompt to url, returning the response if it succeeded. The
+// caller must close its body.
+func (c *GeminiClient) send(ctx context.Context, url, prompt string, opts []Option) (*http.Response, error) {
+	if c.schemaErr != nil {
+		return nil, fmt.Errorf("failed to translate schema for Gemini: %w", c.schemaErr)
+	}
+
 	reqBody := geminiRequest{
-		Contents: []struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-		}{
-			{
-				Parts: []struct {
-					Text string `json:"text"`
-				}{
-					{Text: prompt},
-				},
-			},
-		},
+		Contents: []geminiContent{{Parts: []geminiPart{{Text: prompt}}}},
 	}
+	options := NewOptions(opts...)
+	if options.SystemInstruction != "" {
+		reqBody.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: options.SystemInstruction}}}
+	}
+	reqBody.GenerationConfig = c.generationConfig(options)
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var response geminiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	if len(response.Candidates) == 0 || len(response.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no response from model")
-	}
-
-	return response.Candidates[0].Content.Parts[0].Text, nil
+	return resp, nil
 }
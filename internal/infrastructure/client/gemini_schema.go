@@ -0,0 +1,175 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// geminiSchema is the subset of OpenAPI 3.0 schema objects that Gemini
+// accepts as a responseSchema
+type geminiSchema struct {
+	Type             string                   `json:"type"`
+	Format           string                   `json:"format,omitempty"`
+	Description      string                   `json:"description,omitempty"`
+	Nullable         bool                     `json:"nullable,omitempty"`
+	Enum             []string                 `json:"enum,omitempty"`
+	Properties       map[string]*geminiSchema `json:"properties,omitempty"`
+	PropertyOrdering []string                 `json:"propertyOrdering,omitempty"`
+	Required         []string                 `json:"required,omitempty"`
+	Items            *geminiSchema            `json:"items,omitempty"`
+	MinItems         *int                     `json:"minItems,omitempty"`
+	MaxItems         *int                     `json:"maxItems,omitempty"`
+	Minimum          *float64                 `json:"minimum,omitempty"`
+	Maximum          *float64                 `json:"maximum,omitempty"`
+}
+
+// draft07Schema holds the parts of a draft-07 JSON schema that Gemini can
+// make use of. Anything else, such as patterns or additionalProperties, is
+// left to the ValidatorDecorator to enforce.
+type draft07Schema struct {
+	Type        json.RawMessage `json:"type"`
+	Format      string          `json:"format"`
+	Description string          `json:"description"`
+	Enum        []any           `json:"enum"`
+	Properties  orderedSchemas  `json:"properties"`
+	Required    []string        `json:"required"`
+	Items       *draft07Schema  `json:"items"`
+	MinItems    *int            `json:"minItems"`
+	MaxItems    *int            `json:"maxItems"`
+	Minimum     *float64        `json:"minimum"`
+	Maximum     *float64        `json:"maximum"`
+}
+
+// orderedSchemas is a schema's properties in the order they are written.
+// Gemini generates properties alphabetically unless told otherwise, which
+// would put a wine's reasoning before its name.
+type orderedSchemas struct {
+	keys    []string
+	schemas map[string]*draft07Schema
+}
+
+func (o *orderedSchemas) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+
+	o.schemas = make(map[string]*draft07Schema)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		var schema draft07Schema
+		if err := dec.Decode(&schema); err != nil {
+			return fmt.Errorf("property %q: %w", key, err)
+		}
+		o.keys = append(o.keys, key)
+		o.schemas[key] = &schema
+	}
+	return nil
+}
+
+// newGeminiSchema translates a draft-07 JSON schema into a Gemini
+// responseSchema
+func newGeminiSchema(schema string) (*geminiSchema, error) {
+	var draft draft07Schema
+	if err := json.Unmarshal([]byte(schema), &draft); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return draft.toGemini("#")
+}
+
+// toGemini converts the schema, using path to say where any problem is
+func (s *draft07Schema) toGemini(path string) (*geminiSchema, error) {
+	typ, nullable, err := s.geminiType()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	out := &geminiSchema{
+		Type:        typ,
+		Format:      s.Format,
+		Description: s.Description,
+		Nullable:    nullable,
+		Required:    s.Required,
+		MinItems:    s.MinItems,
+		MaxItems:    s.MaxItems,
+		Minimum:     s.Minimum,
+		Maximum:     s.Maximum,
+	}
+
+	// Gemini only supports enums of strings
+	if typ == "STRING" {
+		for _, value := range s.Enum {
+			if value != nil {
+				out.Enum = append(out.Enum, fmt.Sprint(value))
+			}
+		}
+	}
+
+	if len(s.Properties.keys) > 0 {
+		out.Properties = make(map[string]*geminiSchema, len(s.Properties.keys))
+		for _, key := range s.Properties.keys {
+			property, err := s.Properties.schemas[key].toGemini(path + "/properties/" + key)
+			if err != nil {
+				return nil, err
+			}
+			out.Properties[key] = property
+		}
+		out.PropertyOrdering = s.Properties.keys
+	}
+
+	if s.Items != nil {
+		items, err := s.Items.toGemini(path + "/items")
+		if err != nil {
+			return nil, err
+		}
+		out.Items = items
+	}
+
+	return out, nil
+}
+
+// geminiType returns the schema's type in Gemini's upper case form. A draft-07
+// type list such as ["string", "null"] becomes a nullable string.
+func (s *draft07Schema) geminiType() (string, bool, error) {
+	var types []string
+	if len(s.Type) > 0 {
+		var single string
+		if err := json.Unmarshal(s.Type, &single); err == nil {
+			types = []string{single}
+		} else if err := json.Unmarshal(s.Type, &types); err != nil {
+			return "", false, fmt.Errorf("invalid type %s", s.Type)
+		}
+	}
+
+	nullable := false
+	var remaining []string
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+
+	switch {
+	case len(remaining) == 1:
+		return strings.ToUpper(remaining[0]), nullable, nil
+	case len(remaining) > 1:
+		return "", false, fmt.Errorf("union types %s are not supported", s.Type)
+	case len(s.Properties.keys) > 0:
+		return "OBJECT", nullable, nil
+	case s.Items != nil:
+		return "ARRAY", nullable, nil
+	case len(s.Enum) > 0:
+		return "STRING", nullable, nil
+	default:
+		return "", false, fmt.Errorf("schema has no type")
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewGeminiSchema(t *testing.T) {
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "array",
+		"minItems": 3,
+		"maxItems": 3,
+		"items": {
+			"type": "object",
+			"required": ["name", "color"],
+			"properties": {
+				"name": {"type": "string", "description": "The wine"},
+				"color": {"type": "string", "enum": ["red", "white"]},
+				"vintage": {"type": ["integer", "null"]},
+				"score": {"type": "number", "minimum": 0, "maximum": 1}
+			}
+		}
+	}`
+
+	got, err := newGeminiSchema(schema)
+	if err != nil {
+		t.Fatalf("newGeminiSchema() unexpected error: %v", err)
+	}
+
+	three, zero, one := 3, 0.0, 1.0
+	want := &geminiSchema{
+		Type:     "ARRAY",
+		MinItems: &three,
+		MaxItems: &three,
+		Items: &geminiSchema{
+			Type:     "OBJECT",
+			Required: []string{"name", "color"},
+			Properties: map[string]*geminiSchema{
+				"name":    {Type: "STRING", Description: "The wine"},
+				"color":   {Type: "STRING", Enum: []string{"red", "white"}},
+				"vintage": {Type: "INTEGER", Nullable: true},
+				"score":   {Type: "NUMBER", Minimum: &zero, Maximum: &one},
+			},
+			PropertyOrdering: []string{"name", "color", "vintage", "score"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("newGeminiSchema() = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestNewGeminiSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "invalid JSON", schema: `{`, wantErr: "failed to parse schema"},
+		{name: "union type", schema: `{"type": "object", "properties": {"a": {"type": ["string", "number"]}}}`, wantErr: "#/properties/a: union types"},
+		{name: "no type", schema: `{"type": "array", "items": {}}`, wantErr: "#/items: schema has no type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newGeminiSchema(tt.schema)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newGeminiSchema() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewGeminiSchema_ConfigSchemas makes sure every schema the app sends to
+// Gemini can be translated
+func TestNewGeminiSchema_ConfigSchemas(t *testing.T) {
+	paths, err := filepath.Glob("../../../config/*_schema.json")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no schemas found: %v", err)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			schema, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read schema: %v", err)
+			}
+			if _, err := newGeminiSchema(string(schema)); err != nil {
+				t.Errorf("newGeminiSchema() unexpected error: %v", err)
+			}
+		})
+	}
+}
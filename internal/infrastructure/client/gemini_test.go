@@ -2,11 +2,14 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
 )
 
 // mockReadCloser is a mock implementation of io.ReadCloser
@@ -199,3 +202,250 @@ func TestGeminiClient_Complete_RequestValidation(t *testing.T) {
 		t.Errorf("GeminiClient.Complete() unexpected error: %v", err)
 	}
 }
+
+func TestGeminiClient_Complete_Schema(t *testing.T) {
+	schema := `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`
+
+	tests := []struct {
+		name       string
+		client     LLMClient
+		wantConfig string
+	}{
+		{
+			name:       "no generation config without a schema",
+			client:     NewGeminiClient("test-key", ""),
+			wantConfig: "",
+		},
+		{
+			name:       "JSON constrained by the schema",
+			client:     NewGeminiClient("test-key", "").WithSchema(schema),
+			wantConfig: `{"responseMimeType":"application/json","responseSchema":{"type":"OBJECT","properties":{"name":{"type":"STRING"}},"propertyOrdering":["name"],"required":["name"]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.client.(*GeminiClient).client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					body, err := io.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("failed to read request body: %v", err)
+					}
+					var sent struct {
+						GenerationConfig json.RawMessage `json:"generationConfig"`
+					}
+					if err := json.Unmarshal(body, &sent); err != nil {
+						t.Fatalf("failed to decode request body: %v", err)
+					}
+					if string(sent.GenerationConfig) != tt.wantConfig {
+						t.Errorf("generationConfig = %s, want %s", sent.GenerationConfig, tt.wantConfig)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       &mockReadCloser{strings.NewReader(`{"candidates":[{"content":{"parts":[{"text":"{}"}]}}]}`)},
+					}, nil
+				},
+			}
+
+			if _, err := tt.client.Complete(context.Background(), "test prompt"); err != nil {
+				t.Errorf("GeminiClient.Complete() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGeminiClient_Complete_UntranslatableSchema(t *testing.T) {
+	client := NewGeminiClient("test-key", "").WithSchema(`{"type": ["string", "number"]}`)
+	client.(*GeminiClient).client = &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			t.Error("request sent without its schema")
+			return nil, errors.New("unexpected request")
+		},
+	}
+
+	_, err := client.Complete(context.Background(), "test prompt")
+	if err == nil || !strings.Contains(err.Error(), "failed to translate schema") {
+		t.Errorf("GeminiClient.Complete() error = %v, want schema translation error", err)
+	}
+}
+
+func TestGeminiClient_Complete_Options(t *testing.T) {
+	client := NewGeminiClient("test-key", "")
+	client.client = &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			var sent struct {
+				SystemInstruction json.RawMessage `json:"systemInstruction"`
+				GenerationConfig  json.RawMessage `json:"generationConfig"`
+			}
+			if err := json.Unmarshal(body, &sent); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+
+			wantSystem := `{"parts":[{"text":"You are a sommelier"}]}`
+			if string(sent.SystemInstruction) != wantSystem {
+				t.Errorf("systemInstruction = %s, want %s", sent.SystemInstruction, wantSystem)
+			}
+			wantConfig := `{"temperature":0.3,"topP":0.8,"topK":20,"maxOutputTokens":2048,"stopSequences":["STOP"],"seed":42}`
+			if string(sent.GenerationConfig) != wantConfig {
+				t.Errorf("generationConfig = %s, want %s", sent.GenerationConfig, wantConfig)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       &mockReadCloser{strings.NewReader(`{"candidates":[{"content":{"parts":[{"text":"{}"}]}}]}`)},
+			}, nil
+		},
+	}
+
+	_, err := client.Complete(context.Background(), "test prompt",
+		WithSystemInstruction("You are a sommelier"),
+		WithTemperature(0.3),
+		WithTopP(0.8),
+		WithTopK(20),
+		WithMaxTokens(2048),
+		WithStopSequences("STOP"),
+		WithSeed(42),
+	)
+	if err != nil {
+		t.Errorf("GeminiClient.Complete() unexpected error: %v", err)
+	}
+}
+
+func TestGeminiClient_Stream(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResponse   string
+		mockStatusCode int
+		wantChunks     []string
+		wantResponse   string
+		wantErr        bool
+	}{
+		{
+			name: "streams each event's text",
+			mockResponse: "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"[{\\\"name\\\":\"}]}}]}\r\n\r\n" +
+				"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"\\\"Rioja\\\"}]\"}]}}]}\r\n\r\n" +
+				"data: {\"candidates\":[{\"content\":{\"parts\":[]},\"finishReason\":\"STOP\"}]}\r\n\r\n",
+			mockStatusCode: http.StatusOK,
+			wantChunks:     []string{`[{"name":`, `"Rioja"}]`},
+			wantResponse:   `[{"name":"Rioja"}]`,
+		},
+		{
+			name:           "API error",
+			mockResponse:   `{"error":{"code":429}}`,
+			mockStatusCode: http.StatusTooManyRequests,
+			wantErr:        true,
+		},
+		{
+			name:           "no text",
+			mockResponse:   "data: {\"candidates\":[]}\n\n",
+			mockStatusCode: http.StatusOK,
+			wantErr:        true,
+		},
+		{
+			name:           "malformed event",
+			mockResponse:   "data: {\"candidates\n\n",
+			mockStatusCode: http.StatusOK,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewGeminiClient("test-key", "gemini-2.0-flash")
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					if !strings.Contains(req.URL.String(), ":streamGenerateContent?alt=sse") {
+						t.Errorf("expected the streaming endpoint, got %s", req.URL.String())
+					}
+					return &http.Response{
+						StatusCode: tt.mockStatusCode,
+						Body:       &mockReadCloser{strings.NewReader(tt.mockResponse)},
+					}, nil
+				},
+			}
+
+			var chunks []string
+			got, err := client.Stream(context.Background(), "test prompt", func(chunk string) {
+				chunks = append(chunks, chunk)
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GeminiClient.Stream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.wantResponse {
+				t.Errorf("GeminiClient.Stream() = %v, want %v", got, tt.wantResponse)
+			}
+			if strings.Join(chunks, "|") != strings.Join(tt.wantChunks, "|") {
+				t.Errorf("GeminiClient.Stream() chunks = %q, want %q", chunks, tt.wantChunks)
+			}
+		})
+	}
+}
+
+func TestGeminiClient_Usage(t *testing.T) {
+	tests := []struct {
+		name         string
+		stream       bool
+		mockResponse string
+		want         usage.Tokens
+	}{
+		{
+			name:         "complete",
+			mockResponse: `{"candidates":[{"content":{"parts":[{"text":"{}"}]}}],"usageMetadata":{"promptTokenCount":120,"candidatesTokenCount":30,"totalTokenCount":150}}`,
+			want:         usage.Tokens{Prompt: 120, Completion: 30, Total: 150},
+		},
+		{
+			name:         "thinking tokens count as completion",
+			mockResponse: `{"candidates":[{"content":{"parts":[{"text":"{}"}]}}],"usageMetadata":{"promptTokenCount":120,"candidatesTokenCount":30,"thoughtsTokenCount":400,"totalTokenCount":550}}`,
+			want:         usage.Tokens{Prompt: 120, Completion: 430, Total: 550},
+		},
+		{
+			name:   "stream uses the last event's count",
+			stream: true,
+			mockResponse: "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"{\"}]}}],\"usageMetadata\":{\"promptTokenCount\":120,\"candidatesTokenCount\":1,\"totalTokenCount\":121}}\n\n" +
+				"data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"}\"}]}}],\"usageMetadata\":{\"promptTokenCount\":120,\"candidatesTokenCount\":2,\"totalTokenCount\":122}}\n\n",
+			want: usage.Tokens{Prompt: 120, Completion: 2, Total: 122},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := usage.NewTracker(nil)
+			client := NewGeminiClient("test-key", "gemini-2.0-flash").WithUsage(tracker)
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       &mockReadCloser{strings.NewReader(tt.mockResponse)},
+					}, nil
+				},
+			}
+
+			var err error
+			if tt.stream {
+				_, err = client.Stream(context.Background(), "test prompt", func(string) {})
+			} else {
+				_, err = client.Complete(context.Background(), "test prompt")
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			summary := tracker.Summary()
+			if len(summary) != 1 || summary[0].Model != "gemini-2.0-flash" || summary[0].Calls != 1 {
+				t.Fatalf("unexpected usage %+v", summary)
+			}
+			if summary[0].Tokens != tt.want {
+				t.Errorf("tokens = %+v, want %+v", summary[0].Tokens, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,77 @@
 package client
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
+)
 
 // LLMClient defines the interface for language model clients
 type LLMClient interface {
-	// Complete sends a prompt to the LLM and returns its response
-	Complete(ctx context.Context, prompt string) (string, error)
+	// Complete sends a prompt to the LLM and returns its response. Options
+	// tune how the response is generated.
+	Complete(ctx context.Context, prompt string, opts ...Option) (string, error)
+}
+
+// StructuredOutputClient is implemented by clients that can constrain the
+// model to a JSON schema while it generates, rather than relying on
+// validation afterwards alone
+type StructuredOutputClient interface {
+	LLMClient
+	// WithSchema returns a client whose responses follow the given draft-07
+	// JSON schema
+	WithSchema(schema string) LLMClient
+}
+
+var (
+	// ErrRateLimited is returned when the provider rejects a request because
+	// too many have been sent. Waiting before retrying usually helps.
+	ErrRateLimited = errors.New("rate limited by provider")
+	// ErrOverloaded is returned when the provider is temporarily unable to
+	// serve any requests
+	ErrOverloaded = errors.New("provider overloaded")
+	// ErrInvalidResponse is returned when a response doesn't match the schema
+	// it was validated against. A streamed response will already have been
+	// passed on by then, so callers should say it was invalid.
+	ErrInvalidResponse = errors.New("response failed validation")
+)
+
+// StreamingClient is implemented by clients that can return a response while
+// it is being generated
+type StreamingClient interface {
+	LLMClient
+	// Stream sends a prompt to the LLM, calling onChunk with each piece of
+	// the response as it arrives, and returns the whole response
+	Stream(ctx context.Context, prompt string, onChunk func(chunk string), opts ...Option) (string, error)
+}
+
+// Stream streams the response from llm if it supports streaming. Otherwise
+// it waits for the whole response and passes it to onChunk in one piece.
+func Stream(ctx context.Context, llm LLMClient, prompt string, onChunk func(chunk string), opts ...Option) (string, error) {
+	if streaming, ok := llm.(StreamingClient); ok {
+		return streaming.Stream(ctx, prompt, onChunk, opts...)
+	}
+
+	response, err := llm.Complete(ctx, prompt, opts...)
+	if err != nil {
+		return "", err
+	}
+	onChunk(response)
+	return response, nil
+}
+
+// UsageRecorder receives the tokens used by each request, as reported by the
+// provider
+type UsageRecorder interface {
+	Record(model string, tokens usage.Tokens)
+}
+
+// recordUsage passes tokens to recorder if there is one and the provider
+// reported any
+func recordUsage(recorder UsageRecorder, model string, tokens usage.Tokens) {
+	if recorder == nil || tokens == (usage.Tokens{}) {
+		return
+	}
+	recorder.Record(model, tokens)
 }
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
+)
+
+const (
+	defaultOllamaURL   = "http://localhost:11434"
+	defaultOllamaModel = "llama3.1"
+)
+
+// OllamaClient talks to a local Ollama server through its /api/chat endpoint
+type OllamaClient struct {
+	baseURL string
+	model   string
+	schema  json.RawMessage
+	usage   UsageRecorder
+	client  HTTPClient
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   json.RawMessage `json:"format"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	TopK        *int     `json:"top_k,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+	Seed        *int64   `json:"seed,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaResponse struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error"`
+	// Ollama reports tokens as the number evaluated for the prompt and
+	// for the response
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// NewOllamaClient creates a new Ollama client. An empty baseURL uses Ollama's
+// default of http://localhost:11434.
+func NewOllamaClient(baseURL, model string) *OllamaClient {
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	return &OllamaClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  &http.Client{},
+	}
+}
+
+// WithUsage records the tokens used by each request with recorder
+func (c *OllamaClient) WithUsage(recorder UsageRecorder) *OllamaClient {
+	c.usage = recorder
+	return c
+}
+
+// WithSchema implements the StructuredOutputClient interface. Ollama turns
+// the schema into a grammar, so the model can only produce matching JSON.
+func (c *OllamaClient) WithSchema(schema string) LLMClient {
+	structured := *c
+	structured.schema = json.RawMessage(schema)
+	return &structured
+}
+
+// Complete implements the LLMClient interface
+func (c *OllamaClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	options := NewOptions(opts...)
+	var messages []ollamaMessage
+	if options.SystemInstruction != "" {
+		messages = append(messages, ollamaMessage{Role: "system", Content: options.SystemInstruction})
+	}
+
+	reqBody := ollamaRequest{
+		Model:    c.model,
+		Messages: append(messages, ollamaMessage{Role: "user", Content: prompt}),
+		// Without a schema, plain JSON mode still keeps the model from
+		// wrapping its answer in prose
+		Format: json.RawMessage(`"json"`),
+	}
+	if len(c.schema) > 0 {
+		reqBody.Format = c.schema
+	}
+	modelOptions := ollamaOptions{
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+		TopK:        options.TopK,
+		NumPredict:  options.MaxTokens,
+		Stop:        options.StopSequences,
+		Seed:        options.Seed,
+	}
+	if !reflect.ValueOf(modelOptions).IsZero() {
+		reqBody.Options = &modelOptions
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/chat", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request (is Ollama running at %s?): %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var apiErr ollamaResponse
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, apiErr.Error)
+		}
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if response.Error != "" {
+		return "", fmt.Errorf("model error: %s", response.Error)
+	}
+	recordUsage(c.usage, c.model, usage.Tokens{
+		Prompt:     response.PromptEvalCount,
+		Completion: response.EvalCount,
+	})
+	if response.Message.Content == "" {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return response.Message.Content, nil
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewOllamaClient(t *testing.T) {
+	client := NewOllamaClient("", "")
+	if client.baseURL != defaultOllamaURL {
+		t.Errorf("NewOllamaClient() baseURL = %v, want %v", client.baseURL, defaultOllamaURL)
+	}
+	if client.model != defaultOllamaModel {
+		t.Errorf("NewOllamaClient() model = %v, want %v", client.model, defaultOllamaModel)
+	}
+}
+
+func TestOllamaClient_Complete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResponse   string
+		mockStatusCode int
+		mockErr        error
+		wantResponse   string
+		wantErr        string
+	}{
+		{
+			name:           "successful response",
+			mockResponse:   `{"model":"llama3.1","message":{"role":"assistant","content":"{\"name\":\"Rioja\"}"},"done":true}`,
+			mockStatusCode: http.StatusOK,
+			wantResponse:   `{"name":"Rioja"}`,
+		},
+		{
+			name:           "model not pulled",
+			mockResponse:   `{"error":"model \"llama3.1\" not found, try pulling it first"}`,
+			mockStatusCode: http.StatusNotFound,
+			wantErr:        "try pulling it first",
+		},
+		{
+			name:    "server not running",
+			mockErr: errors.New("connection refused"),
+			wantErr: "is Ollama running",
+		},
+		{
+			name:           "empty message",
+			mockResponse:   `{"message":{"role":"assistant","content":""},"done":true}`,
+			mockStatusCode: http.StatusOK,
+			wantErr:        "no response from model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOllamaClient("", "llama3.1")
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return &http.Response{
+						StatusCode: tt.mockStatusCode,
+						Body:       &mockReadCloser{strings.NewReader(tt.mockResponse)},
+					}, nil
+				},
+			}
+
+			got, err := client.Complete(context.Background(), "test prompt")
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("OllamaClient.Complete() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OllamaClient.Complete() unexpected error: %v", err)
+			}
+			if got != tt.wantResponse {
+				t.Errorf("OllamaClient.Complete() = %v, want %v", got, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestOllamaClient_Complete_Format(t *testing.T) {
+	schema := `{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}`
+
+	tests := []struct {
+		name       string
+		client     LLMClient
+		wantFormat string
+	}{
+		{name: "JSON mode without a schema", client: NewOllamaClient("http://ollama:11434/", "llama3.1"), wantFormat: `"json"`},
+		{name: "schema when given one", client: NewOllamaClient("http://ollama:11434/", "llama3.1").WithSchema(schema), wantFormat: schema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.client.(*OllamaClient).client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					if req.URL.String() != "http://ollama:11434/api/chat" {
+						t.Errorf("unexpected URL %s", req.URL)
+					}
+
+					body, err := io.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("failed to read request body: %v", err)
+					}
+					var sent ollamaRequest
+					if err := json.Unmarshal(body, &sent); err != nil {
+						t.Fatalf("failed to decode request body: %v", err)
+					}
+					if sent.Stream {
+						t.Error("expected stream to be false")
+					}
+					if string(sent.Format) != tt.wantFormat {
+						t.Errorf("format = %s, want %s", sent.Format, tt.wantFormat)
+					}
+					if len(sent.Messages) != 1 || sent.Messages[0].Content != "test prompt" {
+						t.Errorf("unexpected messages %+v", sent.Messages)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       &mockReadCloser{strings.NewReader(`{"message":{"content":"{}"},"done":true}`)},
+					}, nil
+				},
+			}
+
+			if _, err := tt.client.Complete(context.Background(), "test prompt"); err != nil {
+				t.Errorf("OllamaClient.Complete() unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4o-mini"
+)
+
+// OpenAIClient talks to the OpenAI chat completions API, or to any server
+// that implements it, such as llama.cpp, vLLM or LM Studio
+type OpenAIClient struct {
+	baseURL  string
+	apiKey   string
+	model    string
+	jsonMode bool
+	usage    UsageRecorder
+	client   HTTPClient
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Temperature    *float64              `json:"temperature,omitempty"`
+	TopP           *float64              `json:"top_p,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Stop           []string              `json:"stop,omitempty"`
+	Seed           *int64                `json:"seed,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+type openAIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// NewOpenAIClient creates a new chat completions client. An empty baseURL
+// uses OpenAI itself; local servers usually want something like
+// http://localhost:8080/v1 and may not need an API key.
+func NewOpenAIClient(baseURL, apiKey, model string) *OpenAIClient {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+
+	return &OpenAIClient{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		apiKey:   apiKey,
+		model:    model,
+		jsonMode: true,
+		client:   &http.Client{},
+	}
+}
+
+// WithJSONMode sets whether to ask for a JSON object response with
+// response_format, on by default. Turn it off for servers that don't
+// support it.
+func (c *OpenAIClient) WithJSONMode(enabled bool) *OpenAIClient {
+	c.jsonMode = enabled
+	return c
+}
+
+// WithUsage records the tokens used by each request with recorder
+func (c *OpenAIClient) WithUsage(recorder UsageRecorder) *OpenAIClient {
+	c.usage = recorder
+	return c
+}
+
+// Complete implements the LLMClient interface. The chat completions API has
+// no top-k setting, so WithTopK is ignored.
+func (c *OpenAIClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	options := NewOptions(opts...)
+	var messages []openAIMessage
+	if options.SystemInstruction != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: options.SystemInstruction})
+	}
+
+	reqBody := openAIRequest{
+		Model:       c.model,
+		Messages:    append(messages, openAIMessage{Role: "user", Content: prompt}),
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+		MaxTokens:   options.MaxTokens,
+		Stop:        options.StopSequences,
+		Seed:        options.Seed,
+	}
+	if c.jsonMode {
+		reqBody.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var apiErr openAIError
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	recordUsage(c.usage, c.model, usage.Tokens{
+		Prompt:     response.Usage.PromptTokens,
+		Completion: response.Usage.CompletionTokens,
+		Total:      response.Usage.TotalTokens,
+	})
+
+	if len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return response.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewOpenAIClient(t *testing.T) {
+	client := NewOpenAIClient("", "", "")
+	if client.baseURL != defaultOpenAIBaseURL {
+		t.Errorf("NewOpenAIClient() baseURL = %v, want %v", client.baseURL, defaultOpenAIBaseURL)
+	}
+	if client.model != defaultOpenAIModel {
+		t.Errorf("NewOpenAIClient() model = %v, want %v", client.model, defaultOpenAIModel)
+	}
+
+	local := NewOpenAIClient("http://localhost:8080/v1/", "", "llama-3.1-8b")
+	if local.baseURL != "http://localhost:8080/v1" {
+		t.Errorf("NewOpenAIClient() baseURL = %v, want trailing slash trimmed", local.baseURL)
+	}
+}
+
+func TestOpenAIClient_Complete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockResponse   string
+		mockStatusCode int
+		mockErr        error
+		wantResponse   string
+		wantErr        string
+	}{
+		{
+			name:           "successful response",
+			mockResponse:   `{"choices":[{"message":{"role":"assistant","content":"{\"name\":\"Rioja\"}"},"finish_reason":"stop"}]}`,
+			mockStatusCode: http.StatusOK,
+			wantResponse:   `{"name":"Rioja"}`,
+		},
+		{
+			name:           "API error with message",
+			mockResponse:   `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`,
+			mockStatusCode: http.StatusUnauthorized,
+			wantErr:        "Incorrect API key provided",
+		},
+		{
+			name:           "API error without JSON body",
+			mockResponse:   "Bad Gateway",
+			mockStatusCode: http.StatusBadGateway,
+			wantErr:        "status 502: Bad Gateway",
+		},
+		{
+			name:    "HTTP client error",
+			mockErr: errors.New("connection refused"),
+			wantErr: "connection refused",
+		},
+		{
+			name:           "no choices",
+			mockResponse:   `{"choices":[]}`,
+			mockStatusCode: http.StatusOK,
+			wantErr:        "no response from model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOpenAIClient("http://localhost:8080/v1", "test-key", "local-model")
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return &http.Response{
+						StatusCode: tt.mockStatusCode,
+						Body:       &mockReadCloser{strings.NewReader(tt.mockResponse)},
+					}, nil
+				},
+			}
+
+			got, err := client.Complete(context.Background(), "test prompt")
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("OpenAIClient.Complete() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenAIClient.Complete() unexpected error: %v", err)
+			}
+			if got != tt.wantResponse {
+				t.Errorf("OpenAIClient.Complete() = %v, want %v", got, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestOpenAIClient_Complete_RequestValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		jsonMode   bool
+		wantAuth   string
+		wantFormat bool
+	}{
+		{name: "hosted with JSON mode", apiKey: "test-key", jsonMode: true, wantAuth: "Bearer test-key", wantFormat: true},
+		{name: "local server without key or JSON mode", apiKey: "", jsonMode: false, wantAuth: "", wantFormat: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOpenAIClient("http://localhost:8080/v1", tt.apiKey, "local-model").WithJSONMode(tt.jsonMode)
+			client.client = &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					if req.Method != "POST" {
+						t.Errorf("expected POST request, got %s", req.Method)
+					}
+					if req.URL.String() != "http://localhost:8080/v1/chat/completions" {
+						t.Errorf("unexpected URL %s", req.URL)
+					}
+					if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+						t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+					}
+
+					body, err := io.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("failed to read request body: %v", err)
+					}
+					var sent openAIRequest
+					if err := json.Unmarshal(body, &sent); err != nil {
+						t.Fatalf("failed to decode request body: %v", err)
+					}
+					if sent.Model != "local-model" {
+						t.Errorf("model = %q, want local-model", sent.Model)
+					}
+					if len(sent.Messages) != 1 || sent.Messages[0].Role != "user" || sent.Messages[0].Content != "test prompt" {
+						t.Errorf("unexpected messages %+v", sent.Messages)
+					}
+					if (sent.ResponseFormat != nil) != tt.wantFormat {
+						t.Errorf("response_format = %+v, want set %v", sent.ResponseFormat, tt.wantFormat)
+					}
+					if tt.wantFormat && sent.ResponseFormat.Type != "json_object" {
+						t.Errorf("response_format type = %q, want json_object", sent.ResponseFormat.Type)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       &mockReadCloser{strings.NewReader(`{"choices":[{"message":{"content":"ok"}}]}`)},
+					}, nil
+				},
+			}
+
+			if _, err := client.Complete(context.Background(), "test prompt"); err != nil {
+				t.Errorf("OpenAIClient.Complete() unexpected error: %v", err)
+			}
+		})
+	}
+}
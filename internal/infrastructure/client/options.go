@@ -0,0 +1,114 @@
+package client
+
+import "context"
+
+// Options are the generation settings for a single request. Settings left
+// unset keep the provider's defaults.
+type Options struct {
+	// SystemInstruction is sent alongside the prompt to steer the model
+	SystemInstruction string
+	// Temperature controls how random the output is; lower is more focused
+	Temperature *float64
+	// TopP limits sampling to the most likely tokens whose probabilities
+	// add up to it
+	TopP *float64
+	// TopK limits sampling to the K most likely tokens
+	TopK *int
+	// MaxTokens caps the length of the response
+	MaxTokens int
+	// StopSequences end the response as soon as the model produces one
+	StopSequences []string
+	// Seed makes sampling repeatable, where the provider supports it
+	Seed *int64
+}
+
+// Option sets a generation setting
+type Option func(*Options)
+
+// NewOptions applies opts in order, so later options override earlier ones
+func NewOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSystemInstruction sets the system instruction
+func WithSystemInstruction(instruction string) Option {
+	return func(o *Options) {
+		o.SystemInstruction = instruction
+	}
+}
+
+// WithTemperature sets the sampling temperature
+func WithTemperature(temperature float64) Option {
+	return func(o *Options) {
+		o.Temperature = &temperature
+	}
+}
+
+// WithTopP sets nucleus sampling's cumulative probability
+func WithTopP(topP float64) Option {
+	return func(o *Options) {
+		o.TopP = &topP
+	}
+}
+
+// WithTopK sets how many of the most likely tokens are sampled from
+func WithTopK(topK int) Option {
+	return func(o *Options) {
+		o.TopK = &topK
+	}
+}
+
+// WithMaxTokens sets the most tokens the response may contain
+func WithMaxTokens(maxTokens int) Option {
+	return func(o *Options) {
+		o.MaxTokens = maxTokens
+	}
+}
+
+// WithStopSequences sets the sequences that end the response
+func WithStopSequences(stop ...string) Option {
+	return func(o *Options) {
+		o.StopSequences = stop
+	}
+}
+
+// WithSeed sets the sampling seed
+func WithSeed(seed int64) Option {
+	return func(o *Options) {
+		o.Seed = &seed
+	}
+}
+
+// OptionsDecorator wraps an LLMClient and applies default generation options
+// to every request. Options passed to Complete override the defaults.
+type OptionsDecorator struct {
+	client LLMClient
+	opts   []Option
+}
+
+// NewOptionsDecorator creates a new options decorator
+func NewOptionsDecorator(client LLMClient, opts ...Option) *OptionsDecorator {
+	return &OptionsDecorator{
+		client: client,
+		opts:   opts,
+	}
+}
+
+// Complete implements the LLMClient interface
+func (d *OptionsDecorator) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	return d.client.Complete(ctx, prompt, d.merge(opts)...)
+}
+
+// Stream implements the StreamingClient interface
+func (d *OptionsDecorator) Stream(ctx context.Context, prompt string, onChunk func(chunk string), opts ...Option) (string, error) {
+	return Stream(ctx, d.client, prompt, onChunk, d.merge(opts)...)
+}
+
+// merge puts the defaults before opts, so that opts override them
+func (d *OptionsDecorator) merge(opts []Option) []Option {
+	return append(append([]Option{}, d.opts...), opts...)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// mockOptionsClient records the options it was sent
+type mockOptionsClient struct {
+	options Options
+}
+
+func (m *mockOptionsClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	m.options = NewOptions(opts...)
+	return "{}", nil
+}
+
+func TestNewOptions(t *testing.T) {
+	got := NewOptions(
+		WithTemperature(0.2),
+		WithTopP(0.9),
+		WithTopK(40),
+		WithMaxTokens(512),
+		WithStopSequences("END"),
+		WithSeed(7),
+		WithSystemInstruction("Be brief"),
+		WithTemperature(0.5),
+	)
+
+	temperature, topP, topK, seed := 0.5, 0.9, 40, int64(7)
+	want := Options{
+		SystemInstruction: "Be brief",
+		Temperature:       &temperature,
+		TopP:              &topP,
+		TopK:              &topK,
+		MaxTokens:         512,
+		StopSequences:     []string{"END"},
+		Seed:              &seed,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOptionsDecorator_Complete(t *testing.T) {
+	base := &mockOptionsClient{}
+	decorator := NewOptionsDecorator(base, WithTemperature(0.2), WithMaxTokens(1024))
+
+	if _, err := decorator.Complete(context.Background(), "test prompt", WithTemperature(0.9)); err != nil {
+		t.Fatalf("OptionsDecorator.Complete() unexpected error: %v", err)
+	}
+
+	if base.options.Temperature == nil || *base.options.Temperature != 0.9 {
+		t.Errorf("temperature = %v, want the caller's 0.9", base.options.Temperature)
+	}
+	if base.options.MaxTokens != 1024 {
+		t.Errorf("max tokens = %d, want the default 1024", base.options.MaxTokens)
+	}
+}
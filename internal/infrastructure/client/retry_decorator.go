@@ -26,13 +26,13 @@ func NewRetryDecorator(client LLMClient, maxRetries int, initialBackoff, maxBack
 }
 
 // Complete implements the LLMClient interface with exponential backoff retry
-func (d *RetryDecorator) Complete(ctx context.Context, prompt string) (string, error) {
+func (d *RetryDecorator) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
 	var lastErr error
 	backoff := d.initialBackoff
 
 	for attempt := 0; attempt <= d.maxRetries; attempt++ {
 		// Try to get response from underlying client
-		response, err := d.client.Complete(ctx, prompt)
+		response, err := d.client.Complete(ctx, prompt, opts...)
 		if err == nil {
 			return response, nil
 		}
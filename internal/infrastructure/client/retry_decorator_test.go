@@ -14,7 +14,7 @@ type mockLLMClient struct {
 	callCount int
 }
 
-func (m *mockLLMClient) Complete(ctx context.Context, prompt string) (string, error) {
+func (m *mockLLMClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
 	if m.callCount >= len(m.responses) {
 		return "", errors.New("mock client: no more responses")
 	}
@@ -13,8 +13,13 @@ type ValidatorDecorator struct {
 	validator *validator.JSONValidator
 }
 
-// NewValidatorDecorator creates a new validator decorator
+// NewValidatorDecorator creates a new validator decorator. Clients that
+// support structured output are also given the schema to generate against.
 func NewValidatorDecorator(client LLMClient, schema string) *ValidatorDecorator {
+	if structured, ok := client.(StructuredOutputClient); ok {
+		client = structured.WithSchema(schema)
+	}
+
 	return &ValidatorDecorator{
 		client:    client,
 		validator: validator.NewJSONValidator(schema),
@@ -22,9 +27,9 @@ func NewValidatorDecorator(client LLMClient, schema string) *ValidatorDecorator
 }
 
 // Complete wraps the underlying client's Complete method with JSON validation
-func (d *ValidatorDecorator) Complete(ctx context.Context, prompt string) (string, error) {
+func (d *ValidatorDecorator) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
 	// Get response from underlying client
-	response, err := d.client.Complete(ctx, prompt)
+	response, err := d.client.Complete(ctx, prompt, opts...)
 	if err != nil {
 		return "", fmt.Errorf("client error: %w", err)
 	}
@@ -32,7 +37,36 @@ func (d *ValidatorDecorator) Complete(ctx context.Context, prompt string) (strin
 	// Validate and sanitize the response
 	validJSON, err := d.validator.ValidateAndSanitize(response)
 	if err != nil {
-		return "", fmt.Errorf("validation error: %w", err)
+		return "", fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	return validJSON, nil
+}
+
+// Stream implements the StreamingClient interface. Chunks are passed on as
+// they arrive, unvalidated, and the assembled response is validated once it
+// is complete, returning ErrInvalidResponse if it doesn't match the schema.
+// If the underlying client can't stream, the validated response is passed to
+// onChunk in one piece.
+func (d *ValidatorDecorator) Stream(ctx context.Context, prompt string, onChunk func(chunk string), opts ...Option) (string, error) {
+	streaming, ok := d.client.(StreamingClient)
+	if !ok {
+		validJSON, err := d.Complete(ctx, prompt, opts...)
+		if err != nil {
+			return "", err
+		}
+		onChunk(validJSON)
+		return validJSON, nil
+	}
+
+	response, err := streaming.Stream(ctx, prompt, onChunk, opts...)
+	if err != nil {
+		return "", fmt.Errorf("client error: %w", err)
+	}
+
+	validJSON, err := d.validator.ValidateAndSanitize(response)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidResponse, err)
 	}
 
 	return validJSON, nil
@@ -3,6 +3,8 @@ package client
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -12,7 +14,7 @@ type mockValidatorClient struct {
 	err      error
 }
 
-func (m *mockValidatorClient) Complete(ctx context.Context, prompt string) (string, error) {
+func (m *mockValidatorClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
 	return m.response, m.err
 }
 
@@ -186,3 +188,150 @@ func TestValidatorDecoratorWithComplexSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorDecoratorWithArraySchema(t *testing.T) {
+	schema := `{
+		"type": "array",
+		"items": { "type": "string" },
+		"minItems": 1
+	}`
+
+	tests := []struct {
+		name         string
+		response     string
+		wantResponse string
+		wantErr      bool
+	}{
+		{
+			name:         "bare array",
+			response:     `["Rioja", "Barolo"]`,
+			wantResponse: `["Rioja", "Barolo"]`,
+		},
+		{
+			name:         "array wrapped by a JSON object mode",
+			response:     `{"pairings": ["Rioja", "Barolo"]}`,
+			wantResponse: `["Rioja", "Barolo"]`,
+		},
+		{
+			name:     "object with several properties is left alone",
+			response: `{"pairings": ["Rioja"], "note": "hmm"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewValidatorDecorator(&mockValidatorClient{response: tt.response}, schema)
+
+			got, err := client.Complete(context.Background(), "test prompt")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatorDecorator.Complete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && got != tt.wantResponse {
+				t.Errorf("ValidatorDecorator.Complete() = %v, want %v", got, tt.wantResponse)
+			}
+		})
+	}
+}
+
+// mockStructuredClient records the schema it was given
+type mockStructuredClient struct {
+	mockValidatorClient
+	schema string
+}
+
+func (m *mockStructuredClient) WithSchema(schema string) LLMClient {
+	structured := *m
+	structured.schema = schema
+	return &structured
+}
+
+func TestValidatorDecorator_PassesSchemaToStructuredClients(t *testing.T) {
+	schema := `{"type": "object"}`
+	base := &mockStructuredClient{mockValidatorClient: mockValidatorClient{response: `{}`}}
+
+	decorator := NewValidatorDecorator(base, schema)
+
+	structured, ok := decorator.client.(*mockStructuredClient)
+	if !ok {
+		t.Fatalf("decorator wraps %T, want the structured client", decorator.client)
+	}
+	if structured.schema != schema {
+		t.Errorf("schema = %q, want %q", structured.schema, schema)
+	}
+	if base.schema != "" {
+		t.Error("WithSchema should not modify the shared base client")
+	}
+}
+
+// mockStreamingClient streams its response in the given chunks
+type mockStreamingClient struct {
+	chunks []string
+}
+
+func (m *mockStreamingClient) Complete(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	return strings.Join(m.chunks, ""), nil
+}
+
+func (m *mockStreamingClient) Stream(ctx context.Context, prompt string, onChunk func(chunk string), opts ...Option) (string, error) {
+	for _, chunk := range m.chunks {
+		onChunk(chunk)
+	}
+	return strings.Join(m.chunks, ""), nil
+}
+
+func TestValidatorDecorator_Stream(t *testing.T) {
+	schema := `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`
+
+	tests := []struct {
+		name         string
+		client       LLMClient
+		wantChunks   []string
+		wantResponse string
+		wantErr      bool
+	}{
+		{
+			name:         "streams chunks then validates the whole",
+			client:       &mockStreamingClient{chunks: []string{`{"na`, `me": "Rioja"}`}},
+			wantChunks:   []string{`{"na`, `me": "Rioja"}`},
+			wantResponse: `{"name": "Rioja"}`,
+		},
+		{
+			name:       "invalid assembled response",
+			client:     &mockStreamingClient{chunks: []string{`{"colour": `, `"red"}`}},
+			wantChunks: []string{`{"colour": `, `"red"}`},
+			wantErr:    true,
+		},
+		{
+			name:         "non-streaming client gives one validated chunk",
+			client:       &mockValidatorClient{response: "Here you go: {\"name\": \"Rioja\"}"},
+			wantChunks:   []string{`{"name": "Rioja"}`},
+			wantResponse: `{"name": "Rioja"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var chunks []string
+			got, err := NewValidatorDecorator(tt.client, schema).Stream(context.Background(), "test prompt", func(chunk string) {
+				chunks = append(chunks, chunk)
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatorDecorator.Stream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("ValidatorDecorator.Stream() error = %v, want %v", err, ErrInvalidResponse)
+			}
+			if !reflect.DeepEqual(chunks, tt.wantChunks) {
+				t.Errorf("ValidatorDecorator.Stream() chunks = %q, want %q", chunks, tt.wantChunks)
+			}
+			if !tt.wantErr && got != tt.wantResponse {
+				t.Errorf("ValidatorDecorator.Stream() = %v, want %v", got, tt.wantResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+)
+
+const (
+	// PagesNamespace is the cache namespace holding fetched pages
+	PagesNamespace = "pages"
+
+	// maxHeuristicFreshness caps how long a page without caching headers is
+	// considered fresh
+	maxHeuristicFreshness = 24 * time.Hour
+)
+
+// CacheDecorator wraps a Fetcher with a disk cache. It honours Cache-Control
+// and Expires, and revalidates stale pages with ETag and Last-Modified so
+// unchanged pages aren't downloaded again.
+type CacheDecorator struct {
+	fetcher Fetcher
+	store   *cache.Store
+	now     func() time.Time
+}
+
+// cachedPage is the on-disk form of a Page
+type cachedPage struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	FetchedAt  time.Time
+	Expires    time.Time
+}
+
+// NewCacheDecorator creates a new cache decorator
+func NewCacheDecorator(fetcher Fetcher, store *cache.Store) *CacheDecorator {
+	return &CacheDecorator{
+		fetcher: fetcher,
+		store:   store,
+		now:     time.Now,
+	}
+}
+
+// Fetch implements the Fetcher interface, serving fresh pages from the cache
+func (d *CacheDecorator) Fetch(ctx context.Context, rawURL string, header http.Header) (*Page, error) {
+	// Callers doing their own conditional requests bypass the cache
+	if len(header) > 0 {
+		return d.fetcher.Fetch(ctx, rawURL, header)
+	}
+
+	var entry cachedPage
+	found, err := d.store.Get(PagesNamespace, rawURL, &entry)
+	if err != nil {
+		// A broken cache shouldn't stop us fetching the page
+		found = false
+	}
+
+	if found && d.now().Before(entry.Expires) {
+		return entry.page()
+	}
+
+	conditional := http.Header{}
+	if found {
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			conditional.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			conditional.Set("If-Modified-Since", modified)
+		}
+	}
+
+	page, err := d.fetcher.Fetch(ctx, rawURL, conditional)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.StatusCode == http.StatusNotModified && found {
+		// Take any updated freshness information from the 304
+		for _, h := range []string{"Cache-Control", "Expires", "ETag", "Last-Modified", "Date", "Age"} {
+			if v := page.Header.Get(h); v != "" {
+				entry.Header.Set(h, v)
+			}
+		}
+		entry.FetchedAt = page.FetchedAt
+		entry.Expires = expiry(entry.Header, d.now())
+		_ = d.store.Put(PagesNamespace, rawURL, entry)
+		return entry.page()
+	}
+
+	if storable(page.Header) {
+		_ = d.store.Put(PagesNamespace, rawURL, cachedPage{
+			URL:        page.URL.String(),
+			StatusCode: page.StatusCode,
+			Header:     page.Header,
+			Body:       page.Body,
+			FetchedAt:  page.FetchedAt,
+			Expires:    expiry(page.Header, d.now()),
+		})
+	}
+
+	return page, nil
+}
+
+// page converts a cache entry back into a Page
+func (e cachedPage) page() (*Page, error) {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &Page{
+		URL:        u,
+		StatusCode: e.StatusCode,
+		Header:     e.Header,
+		Body:       e.Body,
+		FetchedAt:  e.FetchedAt,
+	}, nil
+}
+
+// cacheControl parses a Cache-Control header into its directives
+func cacheControl(header http.Header) map[string]string {
+	directives := map[string]string{}
+	for _, value := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if key != "" {
+				directives[strings.ToLower(key)] = strings.Trim(val, `"`)
+			}
+		}
+	}
+	return directives
+}
+
+// storable reports whether the response may be cached at all
+func storable(header http.Header) bool {
+	_, noStore := cacheControl(header)["no-store"]
+	return !noStore
+}
+
+// expiry works out when a response stops being fresh. Pages with neither
+// Cache-Control nor Expires are cached for a tenth of the time since they were
+// last modified, up to a day, as RFC 9111 allows.
+func expiry(header http.Header, fetchedAt time.Time) time.Time {
+	directives := cacheControl(header)
+
+	if _, ok := directives["no-cache"]; ok {
+		return fetchedAt
+	}
+
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return fetchedAt
+		}
+		age, _ := strconv.Atoi(header.Get("Age"))
+		return fetchedAt.Add(time.Duration(seconds-age) * time.Second)
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err != nil {
+			// Invalid dates such as "0" mean already expired
+			return fetchedAt
+		}
+		if date, err := http.ParseTime(header.Get("Date")); err == nil {
+			// Measure against the server's clock rather than ours
+			return fetchedAt.Add(t.Sub(date))
+		}
+		return t
+	}
+
+	if modified, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		freshness := fetchedAt.Sub(modified) / 10
+		return fetchedAt.Add(min(max(freshness, 0), maxHeuristicFreshness))
+	}
+
+	return fetchedAt
+}
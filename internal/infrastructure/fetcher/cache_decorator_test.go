@@ -0,0 +1,144 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+)
+
+func TestCacheDecorator_Fetch(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		etag         string
+		advance      time.Duration
+		wantRequests int
+		wantNotMod   int
+	}{
+		{
+			name:         "fresh page served from cache",
+			cacheControl: "max-age=3600",
+			advance:      time.Minute,
+			wantRequests: 1,
+		},
+		{
+			name:         "stale page revalidated with ETag",
+			cacheControl: "max-age=60",
+			etag:         `"v1"`,
+			advance:      time.Hour,
+			wantRequests: 2,
+			wantNotMod:   1,
+		},
+		{
+			name:         "no-cache always revalidates",
+			cacheControl: "no-cache",
+			etag:         `"v1"`,
+			wantRequests: 2,
+			wantNotMod:   1,
+		},
+		{
+			name:         "no-store never cached",
+			cacheControl: "no-store",
+			etag:         `"v1"`,
+			wantRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, notModified := 0, 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if tt.etag != "" && r.Header.Get("If-None-Match") == tt.etag {
+					notModified++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Cache-Control", tt.cacheControl)
+				if tt.etag != "" {
+					w.Header().Set("ETag", tt.etag)
+				}
+				w.Write([]byte("<h1>Coq au vin</h1>"))
+			}))
+			defer server.Close()
+
+			now := time.Now()
+			d := NewCacheDecorator(NewHTTPFetcher(localConfig()), cache.NewStore(t.TempDir()))
+			d.now = func() time.Time { return now }
+
+			for i := 0; i < 2; i++ {
+				page, err := d.Fetch(context.Background(), server.URL, nil)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if string(page.Body) != "<h1>Coq au vin</h1>" {
+					t.Errorf("body = %q", page.Body)
+				}
+				if page.StatusCode != http.StatusOK {
+					t.Errorf("status = %d, want 200", page.StatusCode)
+				}
+				now = now.Add(tt.advance)
+			}
+
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if notModified != tt.wantNotMod {
+				t.Errorf("304 responses = %d, want %d", notModified, tt.wantNotMod)
+			}
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	fetched := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Time
+	}{
+		{
+			name:   "max-age less age",
+			header: http.Header{"Cache-Control": {"public, max-age=600"}, "Age": {"100"}},
+			want:   fetched.Add(500 * time.Second),
+		},
+		{
+			name:   "Expires relative to Date",
+			header: http.Header{"Date": {"Wed, 01 May 2024 10:00:00 GMT"}, "Expires": {"Wed, 01 May 2024 11:00:00 GMT"}},
+			want:   fetched.Add(time.Hour),
+		},
+		{
+			name:   "invalid Expires",
+			header: http.Header{"Expires": {"0"}},
+			want:   fetched,
+		},
+		{
+			name:   "Last-Modified heuristic",
+			header: http.Header{"Last-Modified": {"Tue, 30 Apr 2024 12:00:00 GMT"}},
+			want:   fetched.Add(24 * time.Hour / 10),
+		},
+		{
+			name:   "Last-Modified heuristic capped",
+			header: http.Header{"Last-Modified": {"Mon, 01 Jan 2024 12:00:00 GMT"}},
+			want:   fetched.Add(maxHeuristicFreshness),
+		},
+		{
+			name:   "no caching headers",
+			header: http.Header{},
+			want:   fetched,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expiry(tt.header, fetched); !got.Equal(tt.want) {
+				t.Errorf("expiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
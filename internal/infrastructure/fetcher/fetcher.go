@@ -0,0 +1,248 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultUserAgent    = "Mozilla/5.0 (compatible; pairings/1.0; +https://github.com/kieranajp/pairings)"
+	defaultMaxBodySize  = 10 << 20 // 10 MiB
+	defaultMaxRedirects = 10
+)
+
+var (
+	// ErrNotFound is the cause of a StatusError for 404 and 410 responses
+	ErrNotFound = errors.New("page not found")
+	// ErrForbidden is the cause of a StatusError for 401 and 403 responses
+	ErrForbidden = errors.New("access denied")
+	// ErrRateLimited is the cause of a StatusError for 429 responses
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is the cause of a StatusError for 5xx responses
+	ErrServer = errors.New("server error")
+	// ErrUnexpectedStatus is the cause of a StatusError for any other non-2xx response
+	ErrUnexpectedStatus = errors.New("unexpected status")
+	// ErrTooLarge is returned when a response exceeds the configured maximum size
+	ErrTooLarge = errors.New("response too large")
+	// ErrTooManyRedirects is returned when a page redirects more than allowed
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// StatusError is returned for non-2xx responses. URL is the page that
+// returned the status, after following any redirects. Use errors.Is with the
+// Err* variables above to check the cause.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	cause      error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s returned %d %s", e.cause, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.cause
+}
+
+// newStatusError classifies a non-2xx status code
+func newStatusError(u string, code int) *StatusError {
+	var cause error
+	switch {
+	case code == http.StatusNotFound, code == http.StatusGone:
+		cause = ErrNotFound
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		cause = ErrForbidden
+	case code == http.StatusTooManyRequests:
+		cause = ErrRateLimited
+	case code >= 500:
+		cause = ErrServer
+	default:
+		cause = ErrUnexpectedStatus
+	}
+	return &StatusError{URL: u, StatusCode: code, cause: cause}
+}
+
+// Config controls how pages are fetched
+type Config struct {
+	Timeout      time.Duration
+	UserAgent    string
+	MaxBodySize  int64
+	MaxRedirects int
+
+	// AddressPolicy decides which hosts may be fetched. Nil blocks private
+	// and other non-public addresses.
+	AddressPolicy *AddressPolicy
+}
+
+// DefaultConfig returns sensible defaults for fetching recipe pages
+func DefaultConfig() Config {
+	return Config{
+		Timeout:      defaultTimeout,
+		UserAgent:    defaultUserAgent,
+		MaxBodySize:  defaultMaxBodySize,
+		MaxRedirects: defaultMaxRedirects,
+	}
+}
+
+// Page is a fetched web page with its body transcoded to UTF-8
+type Page struct {
+	URL        *url.URL // The final URL, after any redirects
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	FetchedAt  time.Time
+}
+
+// Fetcher retrieves web pages
+type Fetcher interface {
+	// Fetch downloads the page at rawURL. header may be nil, or carry extra
+	// request headers such as If-None-Match, in which case a 304 Not Modified
+	// response is returned as a Page with an empty body rather than an error.
+	Fetch(ctx context.Context, rawURL string, header http.Header) (*Page, error)
+}
+
+// HTTPFetcher fetches pages over HTTP, enforcing the limits in its Config
+type HTTPFetcher struct {
+	client *http.Client
+	config Config
+}
+
+// NewHTTPFetcher creates a new fetcher with the given configuration. Zero
+// values in config fall back to the defaults; a negative MaxRedirects
+// disables redirects entirely.
+func NewHTTPFetcher(config Config) *HTTPFetcher {
+	defaults := DefaultConfig()
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = defaults.UserAgent
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaults.MaxBodySize
+	}
+	if config.MaxRedirects == 0 {
+		config.MaxRedirects = defaults.MaxRedirects
+	}
+	if config.AddressPolicy == nil {
+		config.AddressPolicy, _ = NewAddressPolicy(nil)
+	}
+
+	// Every connection, including those for redirects, goes through the
+	// policy's dialer. Proxies from the environment are still used, but the
+	// policy checks each request's host before handing it to one.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = config.AddressPolicy.proxy(http.ProxyFromEnvironment)
+	transport.DialContext = config.AddressPolicy.dialContext(&net.Dialer{
+		Timeout:   config.Timeout,
+		KeepAlive: 30 * time.Second,
+	})
+
+	f := &HTTPFetcher{config: config}
+	f.client = &http.Client{
+		Timeout:       config.Timeout,
+		Transport:     transport,
+		CheckRedirect: f.checkRedirect,
+	}
+	return f
+}
+
+// checkRedirect limits the length of redirect chains and checks each
+// redirect against the address policy
+func (f *HTTPFetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > f.config.MaxRedirects {
+		return fmt.Errorf("%w (stopped after %d)", ErrTooManyRedirects, f.config.MaxRedirects)
+	}
+	return f.config.AddressPolicy.CheckURL(req.URL)
+}
+
+// Fetch implements the Fetcher interface
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string, header http.Header) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := f.config.AddressPolicy.CheckURL(req.URL); err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.config.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en;q=1.0, *;q=0.5")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &Page{
+			URL:        resp.Request.URL,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			FetchedAt:  time.Now(),
+		}, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newStatusError(resp.Request.URL.String(), resp.StatusCode)
+	}
+
+	if resp.ContentLength > f.config.MaxBodySize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrTooLarge, resp.ContentLength, f.config.MaxBodySize)
+	}
+
+	// Read one byte past the limit so we can tell if the body was truncated
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > f.config.MaxBodySize {
+		return nil, fmt.Errorf("%w: exceeds limit of %d bytes", ErrTooLarge, f.config.MaxBodySize)
+	}
+
+	body, err = toUTF8(body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		URL:        resp.Request.URL,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		FetchedAt:  time.Now(),
+	}, nil
+}
+
+// toUTF8 transcodes a page body to UTF-8, detecting its encoding from the
+// Content-Type header, a byte order mark or a <meta charset> tag
+func toUTF8(body []byte, contentType string) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+
+	r, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect charset: %w", err)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode charset: %w", err)
+	}
+	return decoded, nil
+}
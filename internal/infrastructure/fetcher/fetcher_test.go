@@ -0,0 +1,163 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestHTTPFetcher_Fetch(t *testing.T) {
+	latin1, _ := charmap.ISO8859_1.NewEncoder().String("<p>Crème brûlée</p>")
+	shiftJIS, _ := japanese.ShiftJIS.NewEncoder().String(`<html><head><meta charset="Shift_JIS"></head><body>肉じゃが</body></html>`)
+
+	tests := []struct {
+		name        string
+		contentType string
+		status      int
+		body        string
+		wantBody    string
+		wantErr     error
+	}{
+		{
+			name:        "UTF-8 page",
+			contentType: "text/html; charset=utf-8",
+			status:      http.StatusOK,
+			body:        "<p>Crème brûlée</p>",
+			wantBody:    "<p>Crème brûlée</p>",
+		},
+		{
+			name:        "Latin-1 from Content-Type",
+			contentType: "text/html; charset=ISO-8859-1",
+			status:      http.StatusOK,
+			body:        latin1,
+			wantBody:    "<p>Crème brûlée</p>",
+		},
+		{
+			name:        "Shift-JIS from meta tag",
+			contentType: "text/html",
+			status:      http.StatusOK,
+			body:        shiftJIS,
+			wantBody:    "肉じゃが",
+		},
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			body:    "<h1>Recipe not found</h1>",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "forbidden",
+			status:  http.StatusForbidden,
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "rate limited",
+			status:  http.StatusTooManyRequests,
+			wantErr: ErrRateLimited,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusBadGateway,
+			wantErr: ErrServer,
+		},
+		{
+			name:    "too large",
+			status:  http.StatusOK,
+			body:    strings.Repeat("a", 2048),
+			wantErr: ErrTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("User-Agent"); got != "test-agent" {
+					t.Errorf("expected user agent test-agent, got %q", got)
+				}
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			f := NewHTTPFetcher(Config{UserAgent: "test-agent", MaxBodySize: 1024, AddressPolicy: UnrestrictedAddressPolicy()})
+			page, err := f.Fetch(context.Background(), server.URL, nil)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Fetch() error = %v, want %v", err, tt.wantErr)
+				}
+				var statusErr *StatusError
+				if errors.As(err, &statusErr) && statusErr.StatusCode != tt.status {
+					t.Errorf("StatusError.StatusCode = %d, want %d", statusErr.StatusCode, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch() unexpected error: %v", err)
+			}
+			if !strings.Contains(string(page.Body), tt.wantBody) {
+				t.Errorf("Fetch() body = %q, want it to contain %q", page.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPFetcher_Redirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<p>moved</p>")
+	})
+	mux.HandleFunc("/deleted", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/gone", http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	f := NewHTTPFetcher(Config{MaxRedirects: 3, AddressPolicy: UnrestrictedAddressPolicy()})
+
+	page, err := f.Fetch(context.Background(), server.URL+"/old", nil)
+	if err != nil {
+		t.Fatalf("Fetch() unexpected error: %v", err)
+	}
+	if page.URL.Path != "/new" {
+		t.Errorf("Page.URL = %s, want final URL after redirect", page.URL)
+	}
+
+	_, err = f.Fetch(context.Background(), server.URL+"/loop", nil)
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrTooManyRedirects)
+	}
+
+	_, err = f.Fetch(context.Background(), server.URL+"/deleted", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Fetch() error = %v, want a StatusError", err)
+	}
+	if statusErr.URL != server.URL+"/gone" {
+		t.Errorf("StatusError.URL = %s, want final URL after redirect", statusErr.URL)
+	}
+}
+
+// localConfig is the default configuration, but allowed to reach httptest
+// servers on loopback
+func localConfig() Config {
+	config := DefaultConfig()
+	config.AddressPolicy = UnrestrictedAddressPolicy()
+	return config
+}
@@ -0,0 +1,242 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+)
+
+const (
+	// RobotsNamespace is the cache namespace holding robots.txt rules
+	RobotsNamespace = "robots"
+
+	// robotsTTL is how long robots.txt rules are trusted, the maximum RFC
+	// 9309 recommends
+	robotsTTL = 24 * time.Hour
+
+	defaultHostInterval    = time.Second
+	defaultHostConcurrency = 2
+)
+
+// ErrDisallowed is returned for pages that robots.txt asks us not to fetch
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// DefaultHostLimiter is a process-wide limiter with the default limits
+var DefaultHostLimiter = NewHostLimiter(0, 0)
+
+// HostLimiter limits the rate and concurrency of requests to each host. A
+// single limiter should be shared by every fetcher in the process so the
+// limits hold however many recipes are fetched at once.
+type HostLimiter struct {
+	interval    time.Duration
+	concurrency int
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// hostState tracks the requests in flight to one host
+type hostState struct {
+	slots chan struct{}
+	next  time.Time
+}
+
+// NewHostLimiter allows one request to a host every interval, with at most
+// concurrency requests in flight. Zero values fall back to the defaults; a
+// negative interval disables rate limiting.
+func NewHostLimiter(interval time.Duration, concurrency int) *HostLimiter {
+	if interval < 0 {
+		interval = 0
+	} else if interval == 0 {
+		interval = defaultHostInterval
+	}
+	if concurrency <= 0 {
+		concurrency = defaultHostConcurrency
+	}
+	return &HostLimiter{
+		interval:    interval,
+		concurrency: concurrency,
+		hosts:       make(map[string]*hostState),
+	}
+}
+
+// Acquire waits until a request to host may be made. delay raises the
+// interval for this request, e.g. to honour a Crawl-delay. The returned
+// function must be called once the request is complete.
+func (l *HostLimiter) Acquire(ctx context.Context, host string, delay time.Duration) (func(), error) {
+	l.mu.Lock()
+	state, ok := l.hosts[host]
+	if !ok {
+		state = &hostState{slots: make(chan struct{}, l.concurrency)}
+		l.hosts[host] = state
+	}
+	l.mu.Unlock()
+
+	select {
+	case state.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-state.slots }
+
+	// Reserve the next free time for this host
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if state.next.After(start) {
+		start = state.next
+	}
+	state.next = start.Add(max(l.interval, delay))
+	l.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+// PoliteDecorator wraps a Fetcher so that it obeys robots.txt and limits how
+// hard it hits each host
+type PoliteDecorator struct {
+	fetcher      Fetcher
+	limiter      *HostLimiter
+	userAgent    string
+	ignoreRobots bool
+	store        *cache.Store
+
+	mu     sync.Mutex
+	robots map[string]*robotsEntry
+}
+
+// robotsEntry is a host's robots.txt rules and when they were fetched
+type robotsEntry struct {
+	Robots    *Robots
+	FetchedAt time.Time
+}
+
+// NewPoliteDecorator creates a new polite decorator. userAgent is matched
+// against the groups in robots.txt.
+func NewPoliteDecorator(fetcher Fetcher, limiter *HostLimiter, userAgent string) *PoliteDecorator {
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	return &PoliteDecorator{
+		fetcher:   fetcher,
+		limiter:   limiter,
+		userAgent: userAgent,
+		robots:    make(map[string]*robotsEntry),
+	}
+}
+
+// WithIgnoreRobots fetches pages even when robots.txt disallows them. Rate
+// limits still apply.
+func (d *PoliteDecorator) WithIgnoreRobots(ignore bool) *PoliteDecorator {
+	d.ignoreRobots = ignore
+	return d
+}
+
+// WithCache keeps robots.txt rules on disk between runs
+func (d *PoliteDecorator) WithCache(store *cache.Store) *PoliteDecorator {
+	d.store = store
+	return d
+}
+
+// Fetch implements the Fetcher interface
+func (d *PoliteDecorator) Fetch(ctx context.Context, rawURL string, header http.Header) (*Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	var delay time.Duration
+	if !d.ignoreRobots {
+		robots, err := d.robotsFor(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		if !robots.Allowed(u.RequestURI()) {
+			return nil, fmt.Errorf("%w: %s", ErrDisallowed, rawURL)
+		}
+		delay = robots.CrawlDelay
+	}
+
+	return d.fetch(ctx, u, rawURL, header, delay)
+}
+
+// fetch makes a rate-limited request
+func (d *PoliteDecorator) fetch(ctx context.Context, u *url.URL, rawURL string, header http.Header, delay time.Duration) (*Page, error) {
+	release, err := d.limiter.Acquire(ctx, u.Host, delay)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return d.fetcher.Fetch(ctx, rawURL, header)
+}
+
+// robotsFor returns the robots.txt rules for a page's site, from memory, disk
+// or the site itself
+func (d *PoliteDecorator) robotsFor(ctx context.Context, u *url.URL) (*Robots, error) {
+	origin := u.Scheme + "://" + u.Host
+
+	d.mu.Lock()
+	entry, ok := d.robots[origin]
+	d.mu.Unlock()
+	if ok && time.Since(entry.FetchedAt) < robotsTTL {
+		return entry.Robots, nil
+	}
+
+	if d.store != nil {
+		var stored robotsEntry
+		if found, err := d.store.Get(RobotsNamespace, origin, &stored); err == nil && found && time.Since(stored.FetchedAt) < robotsTTL {
+			d.remember(origin, &stored)
+			return stored.Robots, nil
+		}
+	}
+
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	page, err := d.fetch(ctx, robotsURL, robotsURL.String(), nil, 0)
+
+	var robots *Robots
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		robots = ParseRobots(page.Body, d.userAgent)
+	case errors.As(err, &statusErr) && statusErr.StatusCode < 500:
+		// No robots.txt (or one we can't see) means no restrictions
+		robots = allowAll
+	case errors.As(err, &statusErr):
+		// Don't remember server errors, the site may recover
+		return disallowAll, nil
+	default:
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+
+	entry = &robotsEntry{Robots: robots, FetchedAt: time.Now()}
+	d.remember(origin, entry)
+	if d.store != nil {
+		_ = d.store.Put(RobotsNamespace, origin, entry)
+	}
+	return robots, nil
+}
+
+// remember keeps a site's rules in memory for the rest of the run
+func (d *PoliteDecorator) remember(origin string, entry *robotsEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.robots[origin] = entry
+}
@@ -0,0 +1,161 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
+)
+
+func TestPoliteDecorator_Robots(t *testing.T) {
+	tests := []struct {
+		name         string
+		robotsStatus int
+		ignoreRobots bool
+		path         string
+		wantErr      error
+	}{
+		{name: "allowed path", robotsStatus: http.StatusOK, path: "/recipes/stew"},
+		{name: "disallowed path", robotsStatus: http.StatusOK, path: "/private/stew", wantErr: ErrDisallowed},
+		{name: "override", robotsStatus: http.StatusOK, ignoreRobots: true, path: "/private/stew"},
+		{name: "no robots.txt", robotsStatus: http.StatusNotFound, path: "/private/stew"},
+		{name: "robots.txt server error", robotsStatus: http.StatusServiceUnavailable, path: "/recipes/stew", wantErr: ErrDisallowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					w.WriteHeader(tt.robotsStatus)
+					w.Write([]byte("User-agent: *\nDisallow: /private/\n"))
+					return
+				}
+				w.Write([]byte("<h1>Stew</h1>"))
+			}))
+			defer server.Close()
+
+			d := NewPoliteDecorator(NewHTTPFetcher(localConfig()), NewHostLimiter(-1, 1), "").
+				WithIgnoreRobots(tt.ignoreRobots)
+
+			_, err := d.Fetch(context.Background(), server.URL+tt.path, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPoliteDecorator_CachesRobots(t *testing.T) {
+	var robotsRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsRequests.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	store := cache.NewStore(t.TempDir())
+	for i := 0; i < 2; i++ {
+		// A new decorator each time, as in separate runs of the CLI
+		d := NewPoliteDecorator(NewHTTPFetcher(localConfig()), NewHostLimiter(-1, 1), "").WithCache(store)
+		for j := 0; j < 2; j++ {
+			if _, err := d.Fetch(context.Background(), server.URL+"/recipes/stew", nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	if got := robotsRequests.Load(); got != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", got)
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	t.Run("concurrency", func(t *testing.T) {
+		limiter := NewHostLimiter(-1, 2)
+
+		var inFlight, peak atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := limiter.Acquire(context.Background(), "example.com", 0)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer release()
+
+				n := inFlight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				inFlight.Add(-1)
+			}()
+		}
+		wg.Wait()
+
+		if got := peak.Load(); got > 2 {
+			t.Errorf("peak concurrency = %d, want at most 2", got)
+		}
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		limiter := NewHostLimiter(20*time.Millisecond, 5)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			release, err := limiter.Acquire(context.Background(), "example.com", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			release()
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("3 requests took %v, want at least 40ms", elapsed)
+		}
+
+		// Other hosts aren't held up
+		start = time.Now()
+		release, err := limiter.Acquire(context.Background(), "other.example.com", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+		if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+			t.Errorf("first request to another host took %v", elapsed)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		limiter := NewHostLimiter(time.Hour, 1)
+		release, err := limiter.Acquire(context.Background(), "example.com", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := limiter.Acquire(ctx, "example.com", 0); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error = %v, want deadline exceeded", err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsRule is a single Allow or Disallow line
+type robotsRule struct {
+	Pattern string
+	Allow   bool
+}
+
+// robotsGroup is a set of rules applying to one or more user agents
+type robotsGroup struct {
+	Agents     []string
+	Rules      []robotsRule
+	CrawlDelay time.Duration
+}
+
+// Robots holds the rules from a robots.txt file that apply to our user agent
+type Robots struct {
+	Rules      []robotsRule
+	CrawlDelay time.Duration
+}
+
+// allowAll is used when a site has no robots.txt
+var allowAll = &Robots{}
+
+// disallowAll is used when a site's robots.txt can't be fetched because of a
+// server error, as RFC 9309 asks
+var disallowAll = &Robots{Rules: []robotsRule{{Pattern: "/"}}}
+
+// ParseRobots parses a robots.txt file, keeping the group that best matches
+// userAgent. Groups naming its product token (e.g. "pairings") win over the
+// "*" group; as in RFC 9309, the rest of the user agent isn't matched, so a
+// "Mozilla" group doesn't apply to "Mozilla/5.0 (compatible; pairings/1.0)".
+func ParseRobots(data []byte, userAgent string) *Robots {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inAgents := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group
+			if !inAgents {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+			}
+			current.Agents = append(current.Agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			// An empty Disallow allows everything, so needs no rule
+			if current == nil || value == "" {
+				continue
+			}
+			current.Rules = append(current.Rules, robotsRule{Pattern: value, Allow: key == "allow"})
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.CrawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	token := productToken(userAgent)
+	var specific, wildcard []*robotsGroup
+	for _, g := range groups {
+		for _, agent := range g.Agents {
+			if agent == "*" {
+				wildcard = append(wildcard, g)
+				break
+			}
+			if agent != "" && agent == token {
+				specific = append(specific, g)
+				break
+			}
+		}
+	}
+
+	matched := specific
+	if len(matched) == 0 {
+		matched = wildcard
+	}
+
+	// Groups for the same agent are merged
+	robots := &Robots{}
+	for _, g := range matched {
+		robots.Rules = append(robots.Rules, g.Rules...)
+		robots.CrawlDelay = max(robots.CrawlDelay, g.CrawlDelay)
+	}
+	return robots
+}
+
+// productToken returns the lower-cased name robots.txt groups are matched
+// against: the product in a "compatible" comment, as in our default user
+// agent, or else the first product
+func productToken(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if _, after, ok := strings.Cut(ua, "(compatible;"); ok {
+		ua = after
+	}
+	ua = strings.TrimSpace(ua)
+	end := strings.IndexFunc(ua, func(r rune) bool {
+		return (r < 'a' || r > 'z') && r != '_' && r != '-'
+	})
+	if end == -1 {
+		return ua
+	}
+	return ua[:end]
+}
+
+// Allowed reports whether path (including any query string) may be fetched.
+// The longest matching rule wins, with Allow winning ties.
+func (r *Robots) Allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allowed, longest := true, -1
+	for _, rule := range r.Rules {
+		if !matchRobotsPattern(rule.Pattern, path) {
+			continue
+		}
+		if n := len(rule.Pattern); n > longest || (n == longest && rule.Allow) {
+			allowed, longest = rule.Allow, n
+		}
+	}
+	return allowed
+}
+
+// matchRobotsPattern matches a path against a robots.txt pattern, where "*"
+// matches any run of characters and a trailing "$" anchors the end
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+
+	return !anchored || rest == ""
+}
@@ -0,0 +1,77 @@
+package fetcher
+
+import (
+	"testing"
+	"time"
+)
+
+const testRobots = `
+# Example robots.txt
+User-agent: *
+Disallow: /admin/
+Disallow: /search
+Allow: /search/help
+Disallow: /*.pdf$
+Crawl-delay: 5
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /
+
+User-agent: Mozilla
+User-agent: compatible
+Disallow: /recipes/
+
+User-agent: pairings
+Disallow: /members/
+Allow: /members/free/
+Crawl-delay: 2.5
+`
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{name: "wildcard group allows recipes", userAgent: "SomeBot/1.0", path: "/recipes/stew", want: true},
+		{name: "wildcard group disallows admin", userAgent: "SomeBot/1.0", path: "/admin/users", want: false},
+		{name: "prefix match", userAgent: "SomeBot/1.0", path: "/search?q=stew", want: false},
+		{name: "longer allow wins", userAgent: "SomeBot/1.0", path: "/search/help", want: true},
+		{name: "anchored wildcard", userAgent: "SomeBot/1.0", path: "/files/stew.pdf", want: false},
+		{name: "anchored wildcard not at end", userAgent: "SomeBot/1.0", path: "/files/stew.pdf.html", want: true},
+		{name: "shared group", userAgent: "Mozilla/5.0 (compatible; Bingbot/2.0)", path: "/recipes/stew", want: false},
+		{name: "specific group replaces wildcard", userAgent: defaultUserAgent, path: "/admin/users", want: true},
+		{name: "specific group disallow", userAgent: defaultUserAgent, path: "/members/stew", want: false},
+		{name: "specific group allow", userAgent: defaultUserAgent, path: "/members/free/stew", want: true},
+		{name: "rest of user agent not matched", userAgent: defaultUserAgent, path: "/recipes/stew", want: true},
+		{name: "first product only", userAgent: "SomeBot/1.0 Mozilla/5.0", path: "/recipes/stew", want: true},
+		{name: "robots.txt always allowed", userAgent: "Googlebot", path: "/robots.txt", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robots := ParseRobots([]byte(testRobots), tt.userAgent)
+			if got := robots.Allowed(tt.path); got != tt.want {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRobots_CrawlDelay(t *testing.T) {
+	if got := ParseRobots([]byte(testRobots), "SomeBot/1.0").CrawlDelay; got != 5*time.Second {
+		t.Errorf("wildcard CrawlDelay = %v, want 5s", got)
+	}
+	if got := ParseRobots([]byte(testRobots), defaultUserAgent).CrawlDelay; got != 2500*time.Millisecond {
+		t.Errorf("specific CrawlDelay = %v, want 2.5s", got)
+	}
+}
+
+func TestParseRobots_Empty(t *testing.T) {
+	robots := ParseRobots([]byte("User-agent: *\nDisallow:\n"), defaultUserAgent)
+	if !robots.Allowed("/anything") {
+		t.Error("empty Disallow should allow everything")
+	}
+}
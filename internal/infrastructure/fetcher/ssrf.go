@@ -0,0 +1,246 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrBlockedAddress is returned when a URL resolves to an address the
+	// AddressPolicy doesn't allow, such as loopback or a private network
+	ErrBlockedAddress = errors.New("address not allowed")
+	// ErrUnsupportedScheme is returned for URLs that aren't http or https
+	ErrUnsupportedScheme = errors.New("unsupported URL scheme")
+)
+
+// reservedPrefixes are ranges that aren't reachable on the public internet but
+// that netip.Addr has no predicate for
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT, and Alibaba Cloud's metadata service
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use IPv4/IPv6 translation
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+	netip.MustParsePrefix("fec0::/10"),       // Deprecated site-local
+	netip.MustParsePrefix("100::/64"),        // Discard-only
+	netip.MustParsePrefix("2001:10::/28"),    // Deprecated ORCHID
+	netip.MustParsePrefix("2001::/32"),       // Teredo, which can tunnel to private IPv4 addresses
+	netip.MustParsePrefix("2002::/16"),       // 6to4, likewise
+	netip.MustParsePrefix("::ffff:0:0:0/96"), // IPv4-translated
+}
+
+// nat64Prefix is the well-known NAT64 prefix. Its addresses embed an IPv4
+// address in their last 32 bits, and on a NAT64 network they reach it.
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// AddressPolicy decides which hosts the fetcher may connect to. Recipe URLs
+// come from users, so without it a URL could reach the machine itself, the
+// local network or a cloud metadata service such as 169.254.169.254.
+//
+// Hostnames are resolved and every address checked before connecting, and
+// the connection is made to the checked address, so a hostname can't resolve
+// to a public address for the check and a private one for the connection.
+// Requests sent through a proxy have their host checked before they're handed
+// to the proxy, which is trusted to connect wherever it is asked to.
+type AddressPolicy struct {
+	unrestricted bool
+	prefixes     []netip.Prefix
+	hosts        map[string]bool
+	lookup       func(ctx context.Context, host string) ([]netip.Addr, error)
+	// proxies holds the addresses of the proxies requests were sent through,
+	// which may be dialled even though they're usually on a private network
+	proxies sync.Map
+}
+
+// NewAddressPolicy creates a policy that blocks loopback, link-local, private
+// and other non-public addresses, except for those in allowlist. Each entry
+// may be a hostname, an IP address or a CIDR range.
+func NewAddressPolicy(allowlist []string) (*AddressPolicy, error) {
+	p := &AddressPolicy{
+		hosts:  make(map[string]bool),
+		lookup: lookupNetIP,
+	}
+
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case strings.Contains(entry, "/"):
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR range %q in allowlist: %w", entry, err)
+			}
+			p.prefixes = append(p.prefixes, prefix.Masked())
+		default:
+			if addr, err := netip.ParseAddr(strings.Trim(entry, "[]")); err == nil {
+				p.prefixes = append(p.prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+				continue
+			}
+			p.hosts[strings.ToLower(strings.TrimSuffix(entry, "."))] = true
+		}
+	}
+
+	return p, nil
+}
+
+// UnrestrictedAddressPolicy allows every address. Only use it where the URLs
+// are trusted, e.g. in tests against a local server.
+func UnrestrictedAddressPolicy() *AddressPolicy {
+	return &AddressPolicy{unrestricted: true, lookup: lookupNetIP}
+}
+
+// CheckURL rejects URLs that aren't http or https, and those whose host is an
+// IP address the policy blocks. Hostnames are checked when connecting.
+func (p *AddressPolicy) CheckURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w %q in %s", ErrUnsupportedScheme, u.Scheme, u.Redacted())
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil && !p.allowed(host, addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// allowed reports whether host may be reached at addr
+func (p *AddressPolicy) allowed(host string, addr netip.Addr) bool {
+	if p.unrestricted || p.hosts[strings.ToLower(strings.TrimSuffix(host, "."))] {
+		return true
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return isPublic(addr)
+}
+
+// isPublic reports whether addr is a globally routable unicast address
+func isPublic(addr netip.Addr) bool {
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return false
+	}
+	if nat64Prefix.Contains(addr) {
+		b := addr.As16()
+		return isPublic(netip.AddrFrom4([4]byte(b[12:])))
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolve looks up host and checks every address it resolves to. A host is
+// refused outright if any of its addresses is blocked, rather than hoping to
+// connect to one of the others.
+func (p *AddressPolicy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	addrs, err := p.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		if !p.allowed(host, addr) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrBlockedAddress, host, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// proxy wraps a Proxy function for http.Transport, such as
+// http.ProxyFromEnvironment, so that a request's host is checked before it
+// is sent through a proxy, which would otherwise connect out of our sight
+func (p *AddressPolicy) proxy(next func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := next(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if !p.unrestricted {
+			if _, err := p.resolve(req.Context(), req.URL.Hostname()); err != nil {
+				return nil, err
+			}
+		}
+		p.proxies.Store(proxyAddress(proxyURL), true)
+		return proxyURL, nil
+	}
+}
+
+// proxyAddress returns the host:port that http.Transport dials for proxyURL
+func proxyAddress(proxyURL *url.URL) string {
+	if port := proxyURL.Port(); port != "" {
+		return net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	port := "80"
+	switch proxyURL.Scheme {
+	case "https":
+		port = "443"
+	case "socks5", "socks5h":
+		port = "1080"
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// dialContext returns a DialContext function for http.Transport that resolves
+// the host itself, checks every address and connects to a checked one
+func (p *AddressPolicy) dialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if _, ok := p.proxies.Load(address); ok {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// lookupNetIP resolves a hostname, or parses it if it is already an address
+func lookupNetIP(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr.Unmap()}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+	for i, addr := range addrs {
+		addrs[i] = addr.Unmap()
+	}
+	return addrs, nil
+}
@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+func TestAddressPolicy_CheckURL(t *testing.T) {
+	policy, err := NewAddressPolicy([]string{"10.1.2.0/24", "192.168.1.5", "recipes.internal"})
+	if err != nil {
+		t.Fatalf("NewAddressPolicy() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{url: "https://example.com/recipe"},
+		{url: "http://93.184.216.34/recipe"},
+		{url: "http://127.0.0.1/", wantErr: ErrBlockedAddress},
+		{url: "http://[::1]:8080/", wantErr: ErrBlockedAddress},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: ErrBlockedAddress},
+		{url: "http://[fd00:ec2::254]/", wantErr: ErrBlockedAddress},
+		{url: "http://100.100.100.200/", wantErr: ErrBlockedAddress},
+		{url: "http://10.0.0.1/", wantErr: ErrBlockedAddress},
+		{url: "http://172.16.0.1/", wantErr: ErrBlockedAddress},
+		{url: "http://0.0.0.0/", wantErr: ErrBlockedAddress},
+		{url: "http://[::ffff:127.0.0.1]/", wantErr: ErrBlockedAddress},
+		{url: "http://[64:ff9b::127.0.0.1]/", wantErr: ErrBlockedAddress},
+		{url: "http://[64:ff9b::a9fe:a9fe]/", wantErr: ErrBlockedAddress},
+		{url: "http://[64:ff9b::93.184.216.34]/"},
+		{url: "http://10.1.2.3/"},
+		{url: "http://192.168.1.5/"},
+		{url: "http://192.168.1.6/", wantErr: ErrBlockedAddress},
+		{url: "file:///etc/passwd", wantErr: ErrUnsupportedScheme},
+		{url: "gopher://example.com/", wantErr: ErrUnsupportedScheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("url.Parse() unexpected error: %v", err)
+			}
+			if err := policy.CheckURL(u); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckURL() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewAddressPolicy_InvalidRange(t *testing.T) {
+	if _, err := NewAddressPolicy([]string{"10.0.0.0/99"}); err == nil {
+		t.Error("NewAddressPolicy() expected error for invalid CIDR range")
+	}
+}
+
+func TestHTTPFetcher_BlocksPrivateHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>internal</p>"))
+	}))
+	defer server.Close()
+
+	policy, _ := NewAddressPolicy(nil)
+	// Pretend DNS resolves the hostname to loopback
+	policy.lookup = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil
+	}
+	f := NewHTTPFetcher(Config{AddressPolicy: policy})
+
+	u, _ := url.Parse(server.URL)
+	if _, err := f.Fetch(context.Background(), "http://innocent.example:"+u.Port()+"/", nil); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrBlockedAddress)
+	}
+	if _, err := f.Fetch(context.Background(), server.URL, nil); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrBlockedAddress)
+	}
+}
+
+func TestHTTPFetcher_ChecksRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metadata", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	})
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	})
+	mux.HandleFunc("/recipe", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>recipe</p>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// The test server itself is allowed, but nothing else private is
+	policy, err := NewAddressPolicy([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("NewAddressPolicy() unexpected error: %v", err)
+	}
+	f := NewHTTPFetcher(Config{AddressPolicy: policy})
+
+	if _, err := f.Fetch(context.Background(), server.URL+"/recipe", nil); err != nil {
+		t.Errorf("Fetch() unexpected error for allowlisted host: %v", err)
+	}
+	if _, err := f.Fetch(context.Background(), server.URL+"/metadata", nil); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrBlockedAddress)
+	}
+	if _, err := f.Fetch(context.Background(), server.URL+"/file", nil); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrUnsupportedScheme)
+	}
+}
+
+func TestHTTPFetcher_ChecksProxiedRequests(t *testing.T) {
+	// A forward proxy on loopback, which the policy would otherwise block
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<p>via " + r.URL.Host + "</p>"))
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	policy, _ := NewAddressPolicy(nil)
+	policy.lookup = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		if host == "innocent.example" {
+			return []netip.Addr{netip.MustParseAddr("10.0.0.1")}, nil
+		}
+		return []netip.Addr{netip.MustParseAddr("93.184.216.34")}, nil
+	}
+	f := NewHTTPFetcher(Config{AddressPolicy: policy})
+	f.client.Transport.(*http.Transport).Proxy = policy.proxy(http.ProxyURL(proxyURL))
+
+	page, err := f.Fetch(context.Background(), "http://example.com/recipe", nil)
+	if err != nil {
+		t.Fatalf("Fetch() unexpected error through proxy: %v", err)
+	}
+	if string(page.Body) != "<p>via example.com</p>" {
+		t.Errorf("Fetch() body = %q, want it to come from the proxy", page.Body)
+	}
+	if _, err := f.Fetch(context.Background(), "http://innocent.example/", nil); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch() error = %v, want %v", err, ErrBlockedAddress)
+	}
+}
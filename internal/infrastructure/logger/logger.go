@@ -10,6 +10,7 @@ import (
 type Logger interface {
 	Info() *zerolog.Event
 	Debug() *zerolog.Event
+	Warn() *zerolog.Event
 	Error() *zerolog.Event
 }
 
@@ -32,8 +33,10 @@ func parseLevel(level string) zerolog.Level {
 	}
 }
 
+// New creates a logger writing to stderr, so that it doesn't get mixed up
+// with the command's output
 func New(level string) Logger {
-	output := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "2006-01-02 15:04:05"}
+	output := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "2006-01-02 15:04:05"}
 	logger := zerolog.New(output).With().Timestamp().Caller().Logger().Level(parseLevel(level))
 	return &zerologLogger{logger: logger}
 }
@@ -46,6 +49,10 @@ func (l *zerologLogger) Debug() *zerolog.Event {
 	return l.logger.Debug()
 }
 
+func (l *zerologLogger) Warn() *zerolog.Event {
+	return l.logger.Warn()
+}
+
 func (l *zerologLogger) Error() *zerolog.Event {
 	return l.logger.Error()
 }
@@ -14,6 +14,8 @@ type Generator interface {
 		styleStr, preferencesStr, occasionStr string,
 	) (string, error)
 	GenerateWinePairingPrompt(r *recipe.Recipe) (string, error)
+	GenerateRecipeExtractionPrompt(text string) (string, error)
+	GenerateRecipeTranslationPrompt(language, recipeJSON string) (string, error)
 }
 
 type generator struct {
@@ -67,5 +69,26 @@ func (g *generator) GenerateWineRecommendationPrompt(
 
 // GenerateWinePairingPrompt generates a prompt for wine pairing
 func (g *generator) GenerateWinePairingPrompt(r *recipe.Recipe) (string, error) {
-	return g.generatePrompt("wine_pairing", r.Title, r.Ingredients, r.Instructions, r.Cuisine)
+	return g.generatePrompt(
+		"wine_pairing",
+		r.Title,
+		r.Description,
+		r.Ingredients,
+		r.FormatKeyIngredients(),
+		r.Instructions,
+		r.Cuisine,
+		r.FormatDishDetails(),
+		r.FormatFlavorProfile(),
+	)
+}
+
+// GenerateRecipeExtractionPrompt generates a prompt for extracting a recipe from page text
+func (g *generator) GenerateRecipeExtractionPrompt(text string) (string, error) {
+	return g.generatePrompt("recipe_extraction", text)
+}
+
+// GenerateRecipeTranslationPrompt generates a prompt for translating a recipe
+// into English
+func (g *generator) GenerateRecipeTranslationPrompt(language, recipeJSON string) (string, error) {
+	return g.generatePrompt("recipe_translation", language, recipeJSON)
 }
@@ -117,19 +117,48 @@ func TestGenerateWineRecommendationPrompt(t *testing.T) {
 func TestGenerateWinePairingPrompt(t *testing.T) {
 	gen, err := NewGenerator(
 		`{"type": "array"}`,
-		`wine_pairing: "Recipe: %s\nIngredients: %v\nMethod: %v\nCuisine: %s\nSchema: %s"`,
+		`wine_pairing: "Recipe: %s\nDescription: %s\nIngredients: %v\nKey: %s\nMethod: %v\nCuisine: %s\nDetails: %s\nFlavour: %s\nSchema: %s"`,
 	)
 	assert.NoError(t, err)
 
 	recipe := &recipe.Recipe{
 		Title:        "Test Recipe",
-		Ingredients:  []string{"ingredient1", "ingredient2"},
+		Ingredients:  []string{"1 onion", "500g beef"},
 		Instructions: []string{"step1", "step2"},
 		Cuisine:      "Test Cuisine",
+		Description:  "A weeknight stew",
+		Category:     []string{"Main course"},
+		Nutrition:    recipe.Nutrition{Calories: "450 kcal", Fat: "20 g"},
 	}
 
-	expected := "Recipe: Test Recipe\nIngredients: [ingredient1 ingredient2]\nMethod: [step1 step2]\nCuisine: Test Cuisine\nSchema: {\"type\": \"array\"}"
+	expected := "Recipe: Test Recipe\nDescription: A weeknight stew\nIngredients: [1 onion 500g beef]\nKey: 500 g beef, 1 onion\nMethod: [step1 step2]\nCuisine: Test Cuisine\nDetails: Course: Main course; Nutrition per serving: 450 kcal, fat 20 g\nFlavour: " + recipe.FormatFlavorProfile() + "\nSchema: {\"type\": \"array\"}"
 	actual, err := gen.GenerateWinePairingPrompt(recipe)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
 }
+
+func TestGenerateRecipeExtractionPrompt(t *testing.T) {
+	gen, err := NewGenerator(
+		`{"type": "object"}`,
+		`recipe_extraction: "Text: %s\nSchema: %s"`,
+	)
+	assert.NoError(t, err)
+
+	expected := "Text: Beef stew. You will need beef.\nSchema: {\"type\": \"object\"}"
+	actual, err := gen.GenerateRecipeExtractionPrompt("Beef stew. You will need beef.")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGenerateRecipeTranslationPrompt(t *testing.T) {
+	gen, err := NewGenerator(
+		`{"type": "object"}`,
+		`recipe_translation: "From: %s\nRecipe: %s\nSchema: %s"`,
+	)
+	assert.NoError(t, err)
+
+	expected := "From: French\nRecipe: {\"ingredients\":[\"2 oignons\"]}\nSchema: {\"type\": \"object\"}"
+	actual, err := gen.GenerateRecipeTranslationPrompt("French", `{"ingredients":["2 oignons"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
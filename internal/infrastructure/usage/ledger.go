@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// appName is the directory created under the user config dir
+const appName = "pairings"
+
+// monthFormat is how entries record the month they were made in
+const monthFormat = "2006-01"
+
+// Entry is one line of the ledger: the usage of one model by one run of a
+// command
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Month   string    `json:"month"`
+	Command string    `json:"command"`
+	Model   string    `json:"model"`
+	Calls   int       `json:"calls"`
+	Tokens
+	// CostUSD is nil when the model has no price
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+}
+
+// Ledger is a local record of usage, kept as a file of JSON lines so runs
+// only ever append to it
+type Ledger struct {
+	path string
+}
+
+// DefaultLedgerPath returns the ledger's path under the user's config dir,
+// e.g. ~/.config/pairings/usage.jsonl on Linux. It is kept out of the cache
+// dir so that clearing the cache doesn't lose it.
+func DefaultLedgerPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config dir: %w", err)
+	}
+	return filepath.Join(dir, appName, "usage.jsonl"), nil
+}
+
+// NewLedger creates a ledger stored at path. The file is created when the
+// first entry is appended.
+func NewLedger(path string) *Ledger {
+	return &Ledger{
+		path: path,
+	}
+}
+
+// Path returns the file the ledger is stored in
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Append records a command's usage of each model
+func (l *Ledger) Append(command string, at time.Time, summary []ModelUsage) error {
+	if len(summary) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create ledger dir: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, m := range summary {
+		entry := Entry{
+			Time:    at.UTC(),
+			Month:   at.UTC().Format(monthFormat),
+			Command: command,
+			Model:   m.Model,
+			Calls:   m.Calls,
+			Tokens:  m.Tokens,
+		}
+		if m.Priced {
+			cost := m.Cost
+			entry.CostUSD = &cost
+		}
+		if err := enc.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write ledger: %w", err)
+		}
+	}
+	return nil
+}
+
+// Entries reads every entry in the ledger. A ledger that doesn't exist yet
+// has none, and lines that can't be read are skipped.
+func (l *Ledger) Entries() ([]Entry, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	return entries, nil
+}
+
+// Summarise adds up entries by month, model and command, ordered by month
+// and then model and command. The totals have no Time, and a CostUSD only if
+// every entry added had one.
+func Summarise(entries []Entry) []Entry {
+	type key struct{ month, model, command string }
+	totals := make(map[key]*Entry)
+	unpriced := make(map[key]bool)
+
+	for _, e := range entries {
+		k := key{e.Month, e.Model, e.Command}
+		total, ok := totals[k]
+		if !ok {
+			total = &Entry{Month: e.Month, Model: e.Model, Command: e.Command, CostUSD: new(float64)}
+			totals[k] = total
+		}
+		total.Calls += e.Calls
+		total.Tokens = total.Tokens.Add(e.Tokens)
+		if e.CostUSD == nil {
+			unpriced[k] = true
+		} else {
+			*total.CostUSD += *e.CostUSD
+		}
+	}
+
+	summary := make([]Entry, 0, len(totals))
+	for k, total := range totals {
+		if unpriced[k] {
+			total.CostUSD = nil
+		}
+		summary = append(summary, *total)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		a, b := summary[i], summary[j]
+		if a.Month != b.Month {
+			return a.Month < b.Month
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Command < b.Command
+	})
+	return summary
+}
@@ -0,0 +1,64 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedger(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), "pairings", "usage.jsonl"))
+
+	entries, err := ledger.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a missing ledger has no entries")
+
+	october := time.Date(2026, 10, 3, 12, 0, 0, 0, time.UTC)
+	november := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, ledger.Append("pair", october, []ModelUsage{
+		{Model: "gemini-2.0-flash", Calls: 2, Tokens: Tokens{Prompt: 100, Completion: 50, Total: 150}, Cost: 0.01, Priced: true},
+		{Model: "llama3.1", Calls: 1, Tokens: Tokens{Prompt: 10, Completion: 5, Total: 15}},
+	}))
+	require.NoError(t, ledger.Append("pair", october.Add(time.Hour), []ModelUsage{
+		{Model: "gemini-2.0-flash", Calls: 1, Tokens: Tokens{Prompt: 10, Completion: 5, Total: 15}, Cost: 0.02, Priced: true},
+	}))
+	require.NoError(t, ledger.Append("preferences", november, []ModelUsage{
+		{Model: "gemini-2.0-flash", Calls: 1, Tokens: Tokens{Prompt: 20, Completion: 10, Total: 30}, Cost: 0.03, Priced: true},
+	}))
+	require.NoError(t, ledger.Append("pair", november, nil))
+
+	// A damaged line shouldn't hide the rest of the ledger
+	f, err := os.OpenFile(ledger.Path(), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("{not json\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err = ledger.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "2026-10", entries[0].Month)
+	assert.Equal(t, "pair", entries[0].Command)
+	assert.Nil(t, entries[1].CostUSD, "unpriced models have no cost")
+
+	summary := Summarise(entries)
+	require.Len(t, summary, 3)
+
+	assert.Equal(t, "2026-10", summary[0].Month)
+	assert.Equal(t, "gemini-2.0-flash", summary[0].Model)
+	assert.Equal(t, 3, summary[0].Calls)
+	assert.Equal(t, 165, summary[0].Tokens.Total)
+	require.NotNil(t, summary[0].CostUSD)
+	assert.InDelta(t, 0.03, *summary[0].CostUSD, 1e-12)
+
+	assert.Equal(t, "llama3.1", summary[1].Model)
+	assert.Nil(t, summary[1].CostUSD)
+
+	assert.Equal(t, "2026-11", summary[2].Month)
+	assert.Equal(t, "preferences", summary[2].Command)
+}
@@ -0,0 +1,78 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Price is what a model charges, in US dollars per million tokens
+type Price struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// snapshotSuffix matches the date that dated model snapshots end in, such as
+// gpt-4o-2024-08-06 or claude-sonnet-4-5-20250929
+var snapshotSuffix = regexp.MustCompile(`-(\d{4}-\d{2}-\d{2}|\d{8})$`)
+
+// PriceTable maps model names to their prices. A model is priced by its exact
+// name, or by the name of the model it is a dated snapshot of. Other names
+// aren't guessed at, as related models such as gpt-4o and gpt-4o-mini can
+// differ in price many times over.
+type PriceTable map[string]Price
+
+// ParsePriceTable parses a price table from YAML
+func ParsePriceTable(data []byte) (PriceTable, error) {
+	var prices PriceTable
+	if err := yaml.Unmarshal(data, &prices); err != nil {
+		return nil, fmt.Errorf("failed to parse price table: %w", err)
+	}
+	return prices, nil
+}
+
+// LoadPriceTable reads a price table from a YAML file
+func LoadPriceTable(path string) (PriceTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table: %w", err)
+	}
+	return ParsePriceTable(data)
+}
+
+// Merge returns a table with the prices in both, those in other taking
+// precedence
+func (p PriceTable) Merge(other PriceTable) PriceTable {
+	merged := make(PriceTable, len(p)+len(other))
+	for model, price := range p {
+		merged[model] = price
+	}
+	for model, price := range other {
+		merged[model] = price
+	}
+	return merged
+}
+
+// Cost returns the cost in US dollars of the tokens used by a model,
+// reporting whether the model has a price
+func (p PriceTable) Cost(model string, tokens Tokens) (float64, bool) {
+	price, ok := p.lookup(model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(tokens.Prompt)*price.Input + float64(tokens.Completion)*price.Output) / 1e6, true
+}
+
+// lookup finds the price for model, or for the model it is a snapshot of
+func (p PriceTable) lookup(model string) (Price, bool) {
+	if price, ok := p[model]; ok {
+		return price, true
+	}
+	if base := snapshotSuffix.ReplaceAllString(model, ""); base != model {
+		price, ok := p[base]
+		return price, ok
+	}
+	return Price{}, false
+}
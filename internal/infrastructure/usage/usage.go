@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"sort"
+	"sync"
+)
+
+// Tokens counts the tokens used by one or more requests
+type Tokens struct {
+	Prompt     int `json:"prompt_tokens"`
+	Completion int `json:"completion_tokens"`
+	Total      int `json:"total_tokens"`
+}
+
+// Add returns the sum of both counts
+func (t Tokens) Add(other Tokens) Tokens {
+	return Tokens{
+		Prompt:     t.Prompt + other.Prompt,
+		Completion: t.Completion + other.Completion,
+		Total:      t.Total + other.Total,
+	}
+}
+
+// ModelUsage is the usage of a single model
+type ModelUsage struct {
+	Model  string
+	Calls  int
+	Tokens Tokens
+	// Cost is in US dollars, and only known if Priced is set
+	Cost   float64
+	Priced bool
+}
+
+// Tracker adds up the usage of each model over a run. It is safe for
+// concurrent use.
+type Tracker struct {
+	mu     sync.Mutex
+	prices PriceTable
+	models map[string]*ModelUsage
+}
+
+// NewTracker creates a tracker that prices usage from prices
+func NewTracker(prices PriceTable) *Tracker {
+	return &Tracker{
+		prices: prices,
+		models: make(map[string]*ModelUsage),
+	}
+}
+
+// Record adds the tokens used by one request to a model
+func (t *Tracker) Record(model string, tokens Tokens) {
+	// Some providers leave the total out
+	if tokens.Total == 0 {
+		tokens.Total = tokens.Prompt + tokens.Completion
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	m, ok := t.models[model]
+	if !ok {
+		m = &ModelUsage{Model: model}
+		t.models[model] = m
+	}
+	m.Calls++
+	m.Tokens = m.Tokens.Add(tokens)
+}
+
+// Summary returns the usage and cost of each model used, by model name
+func (t *Tracker) Summary() []ModelUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary := make([]ModelUsage, 0, len(t.models))
+	for _, m := range t.models {
+		usage := *m
+		usage.Cost, usage.Priced = t.prices.Cost(m.Model, m.Tokens)
+		summary = append(summary, usage)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Model < summary[j].Model
+	})
+	return summary
+}
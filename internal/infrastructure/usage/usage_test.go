@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	prices := PriceTable{"gemini-2.0-flash": {Input: 0.10, Output: 0.40}}
+	tracker := NewTracker(prices)
+
+	tracker.Record("gemini-2.0-flash", Tokens{Prompt: 1000, Completion: 500, Total: 1500})
+	tracker.Record("gemini-2.0-flash", Tokens{Prompt: 2000, Completion: 1000})
+	tracker.Record("llama3.1", Tokens{Prompt: 300, Completion: 200})
+
+	summary := tracker.Summary()
+	require.Len(t, summary, 2)
+
+	assert.Equal(t, "gemini-2.0-flash", summary[0].Model)
+	assert.Equal(t, 2, summary[0].Calls)
+	assert.Equal(t, Tokens{Prompt: 3000, Completion: 1500, Total: 4500}, summary[0].Tokens)
+	assert.True(t, summary[0].Priced)
+	assert.InDelta(t, 0.0009, summary[0].Cost, 1e-12)
+
+	assert.Equal(t, "llama3.1", summary[1].Model)
+	assert.Equal(t, 500, summary[1].Tokens.Total)
+	assert.False(t, summary[1].Priced)
+}
+
+func TestPriceTable(t *testing.T) {
+	prices, err := ParsePriceTable([]byte(`
+gemini-2.0-flash:
+  input: 0.10
+  output: 0.40
+gemini-2.0-flash-lite:
+  input: 0.075
+  output: 0.30
+gpt-4o:
+  input: 2.50
+  output: 10.00
+claude-sonnet-4-5:
+  input: 3.00
+  output: 15.00
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		model    string
+		wantCost float64
+		wantOK   bool
+	}{
+		{model: "gemini-2.0-flash-lite", wantCost: 0.375, wantOK: true},
+		{model: "gpt-4o-2024-08-06", wantCost: 12.5, wantOK: true},
+		{model: "claude-sonnet-4-5-20250929", wantCost: 18, wantOK: true},
+		// Related models aren't priced as the model they are named after
+		{model: "gpt-4o-mini", wantOK: false},
+		{model: "gpt-4o-mini-2024-07-18", wantOK: false},
+		{model: "gemini-2.0-flash-001", wantOK: false},
+		{model: "llama3.1", wantOK: false},
+	}
+	for _, tt := range tests {
+		cost, ok := prices.Cost(tt.model, Tokens{Prompt: 1_000_000, Completion: 1_000_000})
+		assert.Equal(t, tt.wantOK, ok, tt.model)
+		assert.InDelta(t, tt.wantCost, cost, 1e-12, tt.model)
+	}
+
+	merged := prices.Merge(PriceTable{"gemini-2.0-flash": {Input: 1, Output: 2}, "gpt-4o": {Input: 2.5, Output: 10}})
+	assert.Equal(t, Price{Input: 1, Output: 2}, merged["gemini-2.0-flash"])
+	assert.Equal(t, Price{Input: 0.075, Output: 0.30}, merged["gemini-2.0-flash-lite"])
+	assert.Contains(t, merged, "gpt-4o")
+
+	_, err = ParsePriceTable([]byte("gemini: [1, 2]"))
+	assert.Error(t, err)
+}
@@ -28,6 +28,10 @@ func (v *JSONValidator) ValidateAndSanitize(input string) (string, error) {
 		return "", fmt.Errorf("failed to extract JSON: %w", err)
 	}
 
+	// JSON output modes such as OpenAI's json_object only produce objects, so
+	// a model asked for an array may wrap it, e.g. {"pairings": [...]}
+	jsonStr = v.unwrapArray(jsonStr)
+
 	// Then validate against schema
 	if err := v.validate(jsonStr); err != nil {
 		return "", fmt.Errorf("schema validation failed: %w", err)
@@ -74,6 +78,28 @@ func (v *JSONValidator) extractJSON(input string) (string, error) {
 	return jsonStr, nil
 }
 
+// unwrapArray returns the array inside a single-property object when the
+// schema expects an array, and the input unchanged otherwise
+func (v *JSONValidator) unwrapArray(jsonStr string) string {
+	var schema struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(v.schema), &schema); err != nil || schema.Type != "array" {
+		return jsonStr
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jsonStr), &wrapper); err != nil || len(wrapper) != 1 {
+		return jsonStr
+	}
+	for _, value := range wrapper {
+		if trimmed := strings.TrimSpace(string(value)); strings.HasPrefix(trimmed, "[") {
+			return trimmed
+		}
+	}
+	return jsonStr
+}
+
 // validate validates the JSON string against the schema
 func (v *JSONValidator) validate(jsonStr string) error {
 	schemaLoader := gojsonschema.NewStringLoader(v.schema)
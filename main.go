@@ -4,12 +4,17 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/kieranajp/pairings/cmd"
+	appCLI "github.com/kieranajp/pairings/internal/application/cli"
 	"github.com/kieranajp/pairings/internal/domain/recipe"
+	"github.com/kieranajp/pairings/internal/infrastructure/cache"
 	"github.com/kieranajp/pairings/internal/infrastructure/client"
+	"github.com/kieranajp/pairings/internal/infrastructure/fetcher"
 	"github.com/kieranajp/pairings/internal/infrastructure/logger"
 	"github.com/kieranajp/pairings/internal/infrastructure/prompt"
+	"github.com/kieranajp/pairings/internal/infrastructure/usage"
 	"github.com/urfave/cli/v2"
 )
 
@@ -19,35 +24,60 @@ var pairingsSchema string
 //go:embed config/preferences_schema.json
 var preferencesSchema string
 
+//go:embed config/recipe_schema.json
+var recipeSchema string
+
+//go:embed config/translation_schema.json
+var translationSchema string
+
 //go:embed config/prompts.yaml
 var prompts string
 
+//go:embed config/prices.yaml
+var defaultPrices string
+
 var (
-	baseLLM        client.LLMClient
-	prefsLLM       client.LLMClient
-	pairingsLLM    client.LLMClient
-	recipeService  *recipe.Service
-	pairingsPrompt prompt.Generator
-	prefsPrompt    prompt.Generator
-	log            logger.Logger
+	baseLLM         client.LLMClient
+	prefsLLM        client.LLMClient
+	pairingsLLM     client.LLMClient
+	recipeLLM       client.LLMClient
+	translationLLM  client.LLMClient
+	recipeService   *recipe.Service
+	translator      *recipe.Translator
+	pairingsPrompt  prompt.Generator
+	prefsPrompt     prompt.Generator
+	recipePrompt    prompt.Generator
+	translatePrompt prompt.Generator
+	log             logger.Logger
+	tracker         *usage.Tracker
 )
 
 func setup(c *cli.Context) error {
 	log = logger.New(c.String("log-level"))
 
-	// Create base LLM client
-	baseLLM = client.NewGeminiClient(
-		c.String("gemini-api-key"),
-		c.String("gemini-model"),
-	)
+	prices, err := priceTable(c)
+	if err != nil {
+		return err
+	}
+	tracker = usage.NewTracker(prices)
 
-	// Create decorated clients for different schemas
-	prefsLLM = client.NewValidatorDecorator(baseLLM, preferencesSchema)
-	pairingsLLM = client.NewValidatorDecorator(baseLLM, pairingsSchema)
+	// Create base LLM client
+	baseLLM, err = newLLMClient(c, tracker)
+	if err != nil {
+		return err
+	}
 
-	recipeService = recipe.NewService()
+	// Create decorated clients for different schemas. setup runs in a
+	// command's context, so the global generation flags are on its parent.
+	generationOpts := cmd.GenerationOptions(c.Lineage()[1])
+	validated := func(schema string) client.LLMClient {
+		return client.NewOptionsDecorator(client.NewValidatorDecorator(baseLLM, schema), generationOpts...)
+	}
+	prefsLLM = validated(preferencesSchema)
+	pairingsLLM = validated(pairingsSchema)
+	recipeLLM = validated(recipeSchema)
+	translationLLM = validated(translationSchema)
 
-	var err error
 	pairingsPrompt, err = prompt.NewGenerator(pairingsSchema, prompts)
 	if err != nil {
 		return fmt.Errorf("failed to initialize pairings prompt generator: %w", err)
@@ -58,22 +88,186 @@ func setup(c *cli.Context) error {
 		return fmt.Errorf("failed to initialize preferences prompt generator: %w", err)
 	}
 
+	recipePrompt, err = prompt.NewGenerator(recipeSchema, prompts)
+	if err != nil {
+		return fmt.Errorf("failed to initialize recipe prompt generator: %w", err)
+	}
+
+	translatePrompt, err = prompt.NewGenerator(translationSchema, prompts)
+	if err != nil {
+		return fmt.Errorf("failed to initialize translation prompt generator: %w", err)
+	}
+
+	var siteRules recipe.SiteRules
+	if path := c.String("site-rules"); path != "" {
+		siteRules, err = recipe.LoadSiteRules(path)
+		if err != nil {
+			return fmt.Errorf("failed to load site rules: %w", err)
+		}
+	}
+
+	addressPolicy, err := fetcher.NewAddressPolicy(c.StringSlice("allow-host"))
+	if err != nil {
+		return err
+	}
+
+	httpFetcher := fetcher.NewHTTPFetcher(fetcher.Config{
+		Timeout:       c.Duration("fetch-timeout"),
+		UserAgent:     c.String("user-agent"),
+		MaxBodySize:   c.Int64("max-page-size"),
+		MaxRedirects:  c.Int("max-redirects"),
+		AddressPolicy: addressPolicy,
+	})
+	politeFetcher := fetcher.NewPoliteDecorator(
+		httpFetcher,
+		fetcher.NewHostLimiter(c.Duration("host-interval"), c.Int("host-concurrency")),
+		c.String("user-agent"),
+	).WithIgnoreRobots(c.Bool("ignore-robots"))
+
+	recipeService = recipe.NewService().
+		WithExtractors(recipe.DefaultExtractors(siteRules)...).
+		WithLLMFallback(recipe.NewLLMExtractor(recipeLLM, recipePrompt))
+	translator = recipe.NewTranslator(translationLLM, translatePrompt)
+
+	// Cached pages are served without touching the site, so the cache sits
+	// outside the rate limiter
+	var pageFetcher fetcher.Fetcher = politeFetcher
+	if !c.Bool("no-cache") {
+		store, err := cacheStore(c)
+		if err != nil {
+			return err
+		}
+		politeFetcher.WithCache(store)
+		pageFetcher = fetcher.NewCacheDecorator(politeFetcher, store)
+		recipeService.WithCache(store)
+		translator.WithCache(store)
+	}
+	recipeService.WithFetcher(pageFetcher)
+
 	return nil
 }
 
+// newLLMClient creates the client for the provider chosen with --provider,
+// recording its usage with tracker. The same decorators are applied whichever
+// provider it is.
+func newLLMClient(c *cli.Context, tracker *usage.Tracker) (client.LLMClient, error) {
+	switch provider := c.String("provider"); provider {
+	case "gemini":
+		if c.String("gemini-api-key") == "" {
+			return nil, fmt.Errorf("--gemini-api-key or GEMINI_API_KEY is required for the gemini provider")
+		}
+		return client.NewGeminiClient(
+			c.String("gemini-api-key"),
+			c.String("gemini-model"),
+		).WithUsage(tracker), nil
+	case "openai":
+		return client.NewOpenAIClient(
+			c.String("openai-base-url"),
+			c.String("openai-api-key"),
+			c.String("openai-model"),
+		).WithJSONMode(c.Bool("openai-json-mode")).WithUsage(tracker), nil
+	case "ollama":
+		return client.NewOllamaClient(
+			c.String("ollama-url"),
+			c.String("ollama-model"),
+		).WithUsage(tracker), nil
+	case "anthropic":
+		if c.String("anthropic-api-key") == "" {
+			return nil, fmt.Errorf("--anthropic-api-key or ANTHROPIC_API_KEY is required for the anthropic provider")
+		}
+		return client.NewAnthropicClient(
+			c.String("anthropic-api-key"),
+			c.String("anthropic-model"),
+		).WithUsage(tracker), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q, use gemini, openai, ollama or anthropic", provider)
+	}
+}
+
+// priceTable returns the built-in model prices, updated from --price-table
+func priceTable(c *cli.Context) (usage.PriceTable, error) {
+	prices, err := usage.ParsePriceTable([]byte(defaultPrices))
+	if err != nil {
+		return nil, err
+	}
+	if path := c.String("price-table"); path != "" {
+		custom, err := usage.LoadPriceTable(path)
+		if err != nil {
+			return nil, err
+		}
+		prices = prices.Merge(custom)
+	}
+	return prices, nil
+}
+
+// usageLedger opens the ledger at --usage-ledger, or in the user config dir
+// by default
+func usageLedger(c *cli.Context) (*usage.Ledger, error) {
+	path := c.String("usage-ledger")
+	if path == "" {
+		var err error
+		path, err = usage.DefaultLedgerPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return usage.NewLedger(path), nil
+}
+
+// reportUsage appends the run's usage to the ledger and, with --show-usage,
+// prints it. Failing to record usage doesn't fail the command.
+func reportUsage(c *cli.Context, command string) {
+	summary := tracker.Summary()
+	if len(summary) == 0 {
+		return
+	}
+
+	if c.Bool("show-usage") {
+		appCLI.PrintRunUsage(os.Stderr, summary)
+	}
+
+	ledger, err := usageLedger(c)
+	if err == nil {
+		err = ledger.Append(command, time.Now(), summary)
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to record usage")
+	}
+}
+
+// cacheStore opens the cache in --cache-dir, or the user cache dir by default
+func cacheStore(c *cli.Context) (*cache.Store, error) {
+	dir := c.String("cache-dir")
+	if dir == "" {
+		var err error
+		dir, err = cache.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cache.NewStore(dir), nil
+}
+
 func newApp() *cli.App {
 	preferences := cmd.NewPreferencesCommand()
 	pair := cmd.NewPairCommand()
+	cacheClear := cmd.NewCacheClearCommand()
+	usageReport := cmd.NewUsageCommand()
 
 	return &cli.App{
 		Name:  "pairings",
 		Usage: "Find wine pairings for recipes",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
-				Name:     "gemini-api-key",
-				Usage:    "Gemini API key",
-				EnvVars:  []string{"GEMINI_API_KEY"},
-				Required: true,
+				Name:    "provider",
+				Usage:   "LLM provider (gemini, openai, ollama, anthropic)",
+				EnvVars: []string{"PAIRINGS_PROVIDER"},
+				Value:   "gemini",
+			},
+			&cli.StringFlag{
+				Name:    "gemini-api-key",
+				Usage:   "Gemini API key",
+				EnvVars: []string{"GEMINI_API_KEY"},
 			},
 			&cli.StringFlag{
 				Name:    "gemini-model",
@@ -81,13 +275,79 @@ func newApp() *cli.App {
 				EnvVars: []string{"GEMINI_MODEL"},
 				Value:   "gemini-2.0-flash",
 			},
+			&cli.StringFlag{
+				Name:    "openai-base-url",
+				Usage:   "Base URL of an OpenAI-compatible API, e.g. http://localhost:8080/v1 for llama.cpp",
+				EnvVars: []string{"OPENAI_BASE_URL"},
+				Value:   "https://api.openai.com/v1",
+			},
+			&cli.StringFlag{
+				Name:    "openai-api-key",
+				Usage:   "API key for the OpenAI-compatible API (local servers may not need one)",
+				EnvVars: []string{"OPENAI_API_KEY"},
+			},
+			&cli.StringFlag{
+				Name:    "openai-model",
+				Usage:   "Model to use with the OpenAI-compatible API",
+				EnvVars: []string{"OPENAI_MODEL"},
+				Value:   "gpt-4o-mini",
+			},
+			&cli.BoolFlag{
+				Name:    "openai-json-mode",
+				Usage:   "Ask the OpenAI-compatible API for JSON output with response_format (disable for servers without support)",
+				EnvVars: []string{"OPENAI_JSON_MODE"},
+				Value:   true,
+			},
+			&cli.StringFlag{
+				Name:    "ollama-url",
+				Usage:   "URL of the Ollama server",
+				EnvVars: []string{"OLLAMA_URL"},
+				Value:   "http://localhost:11434",
+			},
+			&cli.StringFlag{
+				Name:    "ollama-model",
+				Usage:   "Ollama model to use (it must already be pulled)",
+				EnvVars: []string{"OLLAMA_MODEL"},
+				Value:   "llama3.1",
+			},
+			&cli.StringFlag{
+				Name:    "anthropic-api-key",
+				Usage:   "Anthropic API key",
+				EnvVars: []string{"ANTHROPIC_API_KEY"},
+			},
+			&cli.StringFlag{
+				Name:    "anthropic-model",
+				Usage:   "Anthropic model to use",
+				EnvVars: []string{"ANTHROPIC_MODEL"},
+				Value:   "claude-sonnet-4-5",
+			},
 			&cli.StringFlag{
 				Name:    "log-level",
 				Usage:   "Log level (debug, info, warn, error)",
 				EnvVars: []string{"LOG_LEVEL"},
 				Value:   "info",
 			},
-		},
+			&cli.StringFlag{
+				Name:    "cache-dir",
+				Usage:   "Directory for cached recipe pages (default: user cache dir)",
+				EnvVars: []string{"PAIRINGS_CACHE_DIR"},
+			},
+			&cli.BoolFlag{
+				Name:    "show-usage",
+				Usage:   "Print the tokens used and their cost after each command",
+				EnvVars: []string{"PAIRINGS_SHOW_USAGE"},
+			},
+			&cli.StringFlag{
+				Name:    "price-table",
+				Usage:   "YAML file of model prices per million tokens, overriding the built-in ones",
+				EnvVars: []string{"PAIRINGS_PRICE_TABLE"},
+			},
+			&cli.StringFlag{
+				Name:    "usage-ledger",
+				Usage:   "File that each run's usage is appended to (default: usage.jsonl in the user config dir)",
+				EnvVars: []string{"PAIRINGS_USAGE_LEDGER"},
+			},
+		}, cmd.GenerationFlags()...),
 		Commands: []*cli.Command{
 			{
 				Name:  preferences.Name(),
@@ -97,6 +357,7 @@ func newApp() *cli.App {
 					if err := setup(c); err != nil {
 						return err
 					}
+					defer reportUsage(c, preferences.Name())
 					return preferences.
 						WithLLMClient(prefsLLM).
 						WithPromptGen(prefsPrompt).
@@ -112,19 +373,58 @@ func newApp() *cli.App {
 					if err := setup(c); err != nil {
 						return err
 					}
+					defer reportUsage(c, pair.Name())
 					return pair.
 						WithLLMClient(pairingsLLM).
 						WithRecipeService(recipeService).
 						WithPromptGen(pairingsPrompt).
+						WithTranslator(translator).
 						WithLog(log).
 						Action(c)
 				},
 			},
+			{
+				Name:  usageReport.Name(),
+				Usage: usageReport.Usage(),
+				Flags: usageReport.Flags(),
+				Action: func(c *cli.Context) error {
+					ledger, err := usageLedger(c)
+					if err != nil {
+						return err
+					}
+					return usageReport.
+						WithLedger(ledger).
+						Action(c)
+				},
+			},
+			{
+				Name:  "cache",
+				Usage: "Manage the recipe cache",
+				Subcommands: []*cli.Command{
+					{
+						Name:  cacheClear.Name(),
+						Usage: cacheClear.Usage(),
+						Flags: cacheClear.Flags(),
+						Action: func(c *cli.Context) error {
+							store, err := cacheStore(c)
+							if err != nil {
+								return err
+							}
+							return cacheClear.
+								WithStore(store).
+								Action(c)
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func main() {
+	// Commands that fail before setup, or that don't need it, still log
+	// their errors
+	log = logger.New("info")
 	app := newApp()
 
 	if err := app.Run(os.Args); err != nil {